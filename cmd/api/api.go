@@ -4,16 +4,29 @@ import (
 	"company-name/configs"
 	"company-name/constants"
 	"company-name/internal/auth"
+	"company-name/internal/block-types"
 	"company-name/internal/content-blocks"
+	"company-name/internal/feed"
+	"company-name/internal/files"
+	"company-name/internal/invitation"
+	"company-name/internal/organization"
+	"company-name/internal/page"
+	"company-name/internal/preview"
 	"company-name/internal/user"
+	"company-name/internal/variable"
 	"company-name/pkg/database"
 	"company-name/pkg/email"
+	"company-name/pkg/events"
 	"company-name/pkg/file"
+	"company-name/pkg/sanitizer"
 	"company-name/pkg/validators"
 	"company-name/port/http"
 	"company-name/port/http/handlers"
+	"company-name/port/middleware"
+	"context"
 	"github.com/gin-gonic/gin"
 	"log"
+	"time"
 )
 
 type APIServer struct {
@@ -67,30 +80,83 @@ func (s APIServer) GetEngine() *gin.Engine {
 }
 
 func (s APIServer) RegisterRoutes() error {
+	// Data owned by organizations is only reachable from requests scoped to them
+	scopedDB := database.NewTenantScopedDatabase(s.db, constants.TenantScopedCollections...)
+
 	// Initialize repositories
-	authRepo := auth.NewAuthRepository(s.db)
-	userRepo := user.NewUserRepository(s.db)
-	contentRepo := blocks.NewContentBlockRepository(s.db)
-	userRepo = user.NewUserRepository(s.db)
+	authRepo := auth.NewAuthRepository(scopedDB)
+	userRepo := user.NewUserRepository(scopedDB)
+	pageCache := blocks.NewPageCache(int(s.config.Content.PageCacheSize), time.Duration(s.config.Content.PageCacheTTL)*time.Millisecond)
+	blockEvents := events.NewBroker(int(s.config.Content.StreamHistorySize))
+	contentRepo := blocks.NewEventContentBlockRepository(
+		blocks.NewCachedContentBlockRepository(blocks.NewContentBlockRepository(scopedDB), pageCache),
+		blockEvents,
+	)
+	blockTypeRepo := blocktypes.NewBlockTypeRepository(scopedDB)
+	pageRepo := blocks.NewCachedPageRepository(page.NewPageRepository(scopedDB), pageCache)
+	variableRepo := blocks.NewCachedVariableRepository(variable.NewVariableRepository(scopedDB), pageCache)
+	previewRepo := preview.NewPreviewRepository(scopedDB)
+	filesRepo := files.NewFileRepository(scopedDB)
+	invitationRepo := invitation.NewInvitationRepository(scopedDB)
+	organizationRepo := organization.NewOrganizationRepository(s.db)
 
 	// Initialize services
+	htmlSanitizer := sanitizer.NewSanitizer(sanitizer.ParsePolicy(
+		s.config.Sanitizer.AllowedTags,
+		s.config.Sanitizer.AllowedAttributes,
+		s.config.Sanitizer.AllowedURLSchemes,
+	))
 	authService := auth.NewAuthService(authRepo, s.config, s.validator, s.emailService)
-	contentBlocksService := blocks.NewContentBlocksService(contentRepo, s.validator)
-	userService := user.NewUserService(userRepo, s.validator)
+	blockTypesService := blocktypes.NewBlockTypesService(blockTypeRepo, s.validator)
+	contentBlocksService := blocks.NewContentBlocksService(contentRepo, pageRepo, variableRepo, s.validator, htmlSanitizer, blockTypesService, pageCache)
+	pageService := page.NewPageService(pageRepo, s.validator)
+	variablesService := variable.NewVariablesService(variableRepo, s.validator)
+	previewService := preview.NewPreviewService(previewRepo, pageRepo, s.config)
+	feedService := feed.NewFeedService(contentRepo, pageRepo, s.config)
 	fileService := file.NewFileService(s.config.FileStorage.Directory)
+	userService := user.NewUserService(userRepo, s.validator, s.emailService, fileService, filesRepo)
+	filesService := files.NewFileService(filesRepo, fileService, contentRepo, userRepo)
+	invitationService := invitation.NewInvitationService(invitationRepo, userRepo, s.validator, s.emailService, s.config)
+	organizationService := organization.NewOrganizationService(organizationRepo, userRepo, s.validator)
+
+	// Start background jobs
+	publishScheduler := blocks.NewPublishScheduler(contentRepo, time.Duration(s.config.Content.PublishSchedulerInterval)*time.Millisecond)
+	go publishScheduler.Run(context.Background())
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService, s.validator)
-	contentBlocksHandler := handlers.NewContentBlocksHandler(contentBlocksService, s.validator)
+	contentBlocksHandler := handlers.NewContentBlocksHandler(contentBlocksService, previewService, s.validator, s.config.Content.PageCacheControl)
+	blockEventsHandler := handlers.NewBlockEventsHandler(blockEvents, time.Duration(s.config.Content.StreamHeartbeatInterval)*time.Millisecond)
+	blockTypesHandler := handlers.NewBlockTypesHandler(blockTypesService, s.validator)
+	variablesHandler := handlers.NewVariablesHandler(variablesService, s.validator)
+	previewHandler := handlers.NewPreviewHandler(previewService, s.validator)
+	pageHandler := handlers.NewPageHandler(pageService, s.validator)
+	feedHandler := handlers.NewFeedHandler(feedService, s.config.Content.PageCacheControl)
 	userHandler := handlers.NewUserHandler(userService, s.validator)
-	fileHandler := handlers.NewFileHandler(fileService)
+	fileHandler := handlers.NewFileHandler(filesService, fileService, s.validator)
+	invitationHandler := handlers.NewInvitationHandler(invitationService, s.validator)
+	organizationHandler := handlers.NewOrganizationHandler(organizationService, s.validator)
+
+	// Initialize middlewares
+	authMiddleware := middleware.NewAuthMiddleware(userRepo, organizationService)
+	tenantMiddleware := middleware.NewTenantMiddleware(organizationService)
 
 	router := http.NewRouter(
 		s.engine,
 		authHandler,
 		contentBlocksHandler,
+		blockEventsHandler,
+		blockTypesHandler,
+		variablesHandler,
+		previewHandler,
+		pageHandler,
+		feedHandler,
 		userHandler,
 		fileHandler,
+		invitationHandler,
+		organizationHandler,
+		authMiddleware,
+		tenantMiddleware,
 	)
 
 	router.RegisterRoutes()
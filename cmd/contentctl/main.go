@@ -0,0 +1,164 @@
+package main
+
+import (
+	"company-name/configs"
+	"company-name/internal/block-types"
+	"company-name/internal/content-blocks"
+	"company-name/internal/content-blocks/dtos"
+	"company-name/internal/page"
+	"company-name/internal/variable"
+	"company-name/pkg/database"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/sanitizer"
+	"company-name/pkg/validators"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	validator2 "github.com/go-playground/validator/v10"
+)
+
+const usage = `Administers the content blocks of every organization.
+
+	go run ./cmd/contentctl <command> [flags]
+
+Commands:
+	sanitize [-apply]  report the blocks holding markup the sanitization policy does not allow, and optionally remove it
+	reindex            recompute the stored files every block refers to and its search text, and report the blocks that changed`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "sanitize":
+		sanitize(os.Args[2:])
+	case "reindex":
+		reindex()
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// newContentBlocksService connects to the database without tenant scope, so that the blocks of every organization are
+// reached.
+func newContentBlocksService() blocks.IContentBlocksService {
+	cfg := configs.GetConfig()
+
+	db, err := database.NewDatabase(cfg.DB.ConnectionString, cfg.DB.Name)
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+
+	if err := loc.LoadMessages("assets/locales/localization.json"); err != nil {
+		log.Fatalf("Error loading localization messages: %v", err)
+	}
+
+	htmlSanitizer := sanitizer.NewSanitizer(sanitizer.ParsePolicy(
+		cfg.Sanitizer.AllowedTags,
+		cfg.Sanitizer.AllowedAttributes,
+		cfg.Sanitizer.AllowedURLSchemes,
+	))
+	validatorPkg := validator2.New()
+	validators.RegisterTimeFormatValidators(validatorPkg)
+	validators.RegisterLanguageValidators(validatorPkg)
+	validator := validators.NewValidator(validatorPkg)
+
+	blockTypesService := blocktypes.NewBlockTypesService(blocktypes.NewBlockTypeRepository(db), validator)
+
+	return blocks.NewContentBlocksService(
+		blocks.NewContentBlockRepository(db),
+		page.NewPageRepository(db),
+		variable.NewVariableRepository(db),
+		validator,
+		htmlSanitizer,
+		blockTypesService,
+		nil,
+	)
+}
+
+// sanitize re-applies the sanitization policy to the stored blocks, e.g. after it was tightened. Exits with status 1
+// when blocks hold disallowed markup and were not all sanitized.
+func sanitize(args []string) {
+	flags := flag.NewFlagSet("sanitize", flag.ExitOnError)
+	apply := flags.Bool("apply", false, "remove the disallowed markup instead of only reporting it")
+	_ = flags.Parse(args)
+
+	service := newContentBlocksService()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	response, err := service.SanitizeBlocks(ctx, &dtos.SanitizeBlocksRequest{Apply: *apply})
+	if err != nil {
+		log.Fatalf("Error sanitizing content blocks: %v", err)
+	}
+
+	if len(response.Blocks) == 0 {
+		fmt.Println("Content blocks are clean")
+		return
+	}
+
+	failed := false
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TENANT\tPAGE\tSECTION\tLOCALE\tDRAFT\tPUBLISHED\tERROR")
+	for _, block := range response.Blocks {
+		failed = failed || block.Error != ""
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			orDash(block.TenantID), block.Page, block.Section, block.Locale,
+			orDash(strings.Join(block.DraftRejected, " ")), orDash(strings.Join(block.PublishedRejected, " ")), orDash(block.Error))
+	}
+	writer.Flush()
+
+	if !response.Applied || failed {
+		os.Exit(1)
+	}
+}
+
+// reindex recomputes the data derived from the content of the stored blocks. Exits with status 1 when some blocks
+// could not be saved.
+func reindex() {
+	service := newContentBlocksService()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	response, err := service.ReindexBlocks(ctx)
+	if err != nil {
+		log.Fatalf("Error reindexing content blocks: %v", err)
+	}
+
+	if len(response.Blocks) == 0 {
+		fmt.Println("Content blocks are up to date")
+		return
+	}
+
+	failed := false
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TENANT\tPAGE\tSECTION\tLOCALE\tFILES\tERROR")
+	for _, block := range response.Blocks {
+		failed = failed || block.Error != ""
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\t%s\n",
+			orDash(block.TenantID), block.Page, block.Section, block.Locale, block.Files, orDash(block.Error))
+	}
+	writer.Flush()
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
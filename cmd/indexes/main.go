@@ -0,0 +1,58 @@
+package main
+
+import (
+	"company-name/configs"
+	"company-name/entities"
+	"company-name/pkg/database"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// Reports the drift between the indexes declared on the entities and the ones existing in the database, and
+// optionally creates the missing ones. Exits with status 1 when drift remains.
+//
+//	go run ./cmd/indexes [-apply]
+func main() {
+	apply := flag.Bool("apply", false, "create the missing indexes before reporting")
+	flag.Parse()
+
+	cfg := configs.GetConfig()
+
+	db, err := database.NewDatabase(cfg.DB.ConnectionString, cfg.DB.Name)
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	definitions := entities.Indexes()
+	if *apply {
+		if err := db.EnsureIndexes(ctx, definitions); err != nil {
+			log.Printf("Error creating indexes: %v", err)
+		}
+	}
+
+	drifts, err := db.IndexDrift(ctx, definitions)
+	if err != nil {
+		log.Fatalf("Error reading indexes: %v", err)
+	}
+
+	if len(drifts) == 0 {
+		fmt.Println("Indexes are in sync")
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "COLLECTION\tINDEX\tSTATUS\tDETAIL")
+	for _, drift := range drifts {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", drift.Collection, drift.Name, drift.Status, drift.Detail)
+	}
+	writer.Flush()
+	os.Exit(1)
+}
@@ -3,14 +3,17 @@ package main
 import (
 	"company-name/cmd/api"
 	"company-name/configs"
+	"company-name/entities"
 	"company-name/pkg/database"
 	"company-name/pkg/email"
 	loc "company-name/pkg/localization"
 	"company-name/pkg/validators"
+	"context"
 	"fmt"
 	validator2 "github.com/go-playground/validator/v10"
 	"log"
 	"os"
+	"time"
 )
 
 func main() {
@@ -23,6 +26,13 @@ func main() {
 	}
 	log.Println("Connected to database")
 
+	// Create the indexes declared on the entities; drift can be inspected with `go run ./cmd/indexes`
+	indexesCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	if err := db.EnsureIndexes(indexesCtx, entities.Indexes()); err != nil {
+		log.Printf("Error creating indexes: %v", err)
+	}
+	cancel()
+
 	// Set the path to the localization file
 	localizationFilePath := "assets/locales/localization.json"
 
@@ -42,6 +52,7 @@ func main() {
 
 	validatorPkg := validator2.New()
 	validators.RegisterTimeFormatValidators(validatorPkg)
+	validators.RegisterLanguageValidators(validatorPkg)
 	validator := validators.NewValidator(validatorPkg)
 
 	emailService := email.NewEmailService(cfg.Email.Host, cfg.Email.Port, cfg.Email.Username, cfg.Email.Password, cfg.Email.From)
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"company-name/configs"
+	"company-name/constants"
+	"company-name/pkg/database"
+	"company-name/pkg/database/seeder"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Applies the fixtures of a directory to the database of an environment. The variables of .env.<environment>, when the
+// file exists, take precedence over the ones of .env, so that the database of another environment can be seeded.
+// Exits with status 1 when a fixture fails.
+//
+//	go run ./cmd/seed [-env development] [-dir assets/fixtures] [-dry-run]
+func main() {
+	environment := flag.String("env", "", "environment to seed, APP_ENVIRONMENT by default")
+	dir := flag.String("dir", "assets/fixtures", "directory of the fixtures")
+	dryRun := flag.Bool("dry-run", false, "report the fixtures that would be applied without writing anything")
+	flag.Parse()
+
+	if *environment != "" {
+		if err := godotenv.Load(".env." + *environment); err != nil && !os.IsNotExist(err) {
+			log.Fatalf("Error loading .env.%s: %v", *environment, err)
+		}
+	}
+
+	cfg := configs.GetConfig()
+	if *environment == "" {
+		*environment = cfg.App.Environment
+	}
+
+	fixtures, err := seeder.LoadFixtures(*dir)
+	if err != nil {
+		log.Fatalf("Error loading fixtures: %v", err)
+	}
+
+	db, err := database.NewDatabase(cfg.DB.ConnectionString, cfg.DB.Name)
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	scopedDb := database.NewTenantScopedDatabase(db, constants.TenantScopedCollections...)
+	results, seedErr := seeder.NewSeeder(scopedDb, *environment).Seed(ctx, fixtures, *dryRun)
+
+	fmt.Printf("Seeding %s from %s\n", *environment, *dir)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "FIXTURE\tVERSION\tFILE\tSTATUS\tDETAIL")
+	for _, result := range results {
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%s\n", result.Fixture, result.Version, result.Path, result.Status, result.Detail)
+	}
+	writer.Flush()
+
+	if seedErr != nil {
+		log.Printf("Error seeding: %v", seedErr)
+		os.Exit(1)
+	}
+}
@@ -18,14 +18,19 @@ type Config struct {
 		Port            string
 		Environment     string
 		VerificationUrl string
+		InvitationUrl   string
+		PreviewUrl      string
+		SiteUrl         string // Base URL of the public site, for the links of the sitemap and the news feed
 	}
 	DB struct {
 		ConnectionString string
 		Name             string
 	}
 	JWT struct {
-		Secret     string
-		Expiration int64
+		Secret               string
+		Expiration           int64
+		InvitationExpiration int64
+		PreviewExpiration    int64
 	}
 	Email struct {
 		Username string
@@ -37,6 +42,19 @@ type Config struct {
 	FileStorage struct {
 		Directory string
 	}
+	Content struct {
+		PublishSchedulerInterval int64
+		PageCacheSize            int64
+		PageCacheTTL             int64
+		PageCacheControl         string
+		StreamHeartbeatInterval  int64 // Between the heartbeats of the streams of page events, in milliseconds
+		StreamHistorySize        int64 // Page events kept for the streams that resume after a disconnection
+	}
+	Sanitizer struct {
+		AllowedTags       string
+		AllowedAttributes string
+		AllowedURLSchemes string
+	}
 }
 
 var (
@@ -71,6 +89,9 @@ func initializeConfig() (*Config, error) {
 	config.App.Port = getEnv("APP_PORT", "8080")
 	config.App.Environment = getEnv("APP_ENVIRONMENT", "development")
 	config.App.VerificationUrl = getEnv("EMAIL_VERIFICATION_URL", "https://example.com/verify")
+	config.App.InvitationUrl = getEnv("INVITATION_URL", "https://example.com/accept-invite")
+	config.App.PreviewUrl = getEnv("PREVIEW_URL", "https://example.com/preview")
+	config.App.SiteUrl = getEnv("SITE_URL", "https://example.com")
 
 	// DB
 	config.DB.ConnectionString = getEnv("DB_CONNECTION_STRING", "mongodb://localhost:27017")
@@ -82,6 +103,10 @@ func initializeConfig() (*Config, error) {
 
 	config.JWT.Expiration = getEnvAsInt("JWT_EXPIRATION_IN_MILLISECONDS", 3600000)
 
+	config.JWT.InvitationExpiration = getEnvAsInt("INVITATION_EXPIRATION_IN_MILLISECONDS", 259200000)
+
+	config.JWT.PreviewExpiration = getEnvAsInt("PREVIEW_EXPIRATION_IN_MILLISECONDS", 604800000)
+
 	// Email
 	config.Email.Host = getEnv("EMAIL_HOST", "smtp.example.com")
 	config.Email.Port = getEnv("EMAIL_PORT", "587")
@@ -92,6 +117,19 @@ func initializeConfig() (*Config, error) {
 	// File Storage
 	config.FileStorage.Directory = getEnv("FILE_STORAGE_DIRECTORY", "uploads")
 
+	// Content
+	config.Content.PublishSchedulerInterval = getEnvAsPositiveInt("PUBLISH_SCHEDULER_INTERVAL_IN_MILLISECONDS", 60000)
+	config.Content.PageCacheSize = getEnvAsInt("PAGE_CACHE_SIZE", 500)
+	config.Content.PageCacheTTL = getEnvAsInt("PAGE_CACHE_TTL_IN_MILLISECONDS", 300000)
+	config.Content.PageCacheControl = getEnv("PAGE_CACHE_CONTROL", constants.DefaultPageCacheControl)
+	config.Content.StreamHeartbeatInterval = getEnvAsPositiveInt("STREAM_HEARTBEAT_INTERVAL_IN_MILLISECONDS", 15000)
+	config.Content.StreamHistorySize = getEnvAsInt("STREAM_HISTORY_SIZE", 1000)
+
+	// Sanitizer
+	config.Sanitizer.AllowedTags = getEnv("SANITIZER_ALLOWED_TAGS", constants.DefaultSanitizerAllowedTags)
+	config.Sanitizer.AllowedAttributes = getEnv("SANITIZER_ALLOWED_ATTRIBUTES", constants.DefaultSanitizerAllowedAttributes)
+	config.Sanitizer.AllowedURLSchemes = getEnv("SANITIZER_ALLOWED_URL_SCHEMES", constants.DefaultSanitizerAllowedURLSchemes)
+
 	return config, nil
 }
 
@@ -112,3 +150,13 @@ func getEnvAsInt(key string, fallback int64) int64 {
 	}
 	return fallback
 }
+
+// getEnvAsPositiveInt reads a setting that must be above zero, such as an interval, falling back otherwise.
+func getEnvAsPositiveInt(key string, fallback int64) int64 {
+	value := getEnvAsInt(key, fallback)
+	if value <= 0 {
+		log.Printf(constants.ConfiginvalidValueMessageErrorMessage, key, fallback)
+		return fallback
+	}
+	return value
+}
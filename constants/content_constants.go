@@ -0,0 +1,85 @@
+package constants
+
+const (
+	BlockStatusDraft     = "draft"
+	BlockStatusPublished = "published"
+)
+
+// Formats the content of a block can be written in.
+const (
+	BlockFormatHTML     = "html"
+	BlockFormatMarkdown = "markdown"
+	BlockFormatText     = "text"
+	BlockFormatJSON     = "json" // Structured content of typed blocks, validated against the schema of their type
+)
+
+// Ways the content of a block can be served.
+const (
+	RenderRaw  = "raw"  // As written, whatever its format
+	RenderHTML = "html" // As HTML, Markdown being rendered and plain text escaped
+	RenderText = "text" // As plain text, markup being stripped
+)
+
+// Default allowlist HTML block content is sanitized against, see the SANITIZER_* settings.
+const (
+	DefaultSanitizerAllowedTags = "p,br,hr,h1,h2,h3,h4,h5,h6,strong,b,em,i,u,s,del,ins,sub,sup,small,mark,abbr,cite,q," +
+		"blockquote,code,pre,ul,ol,li,dl,dt,dd,a,img,figure,figcaption,table,caption,thead,tbody,tfoot,tr,th,td," +
+		"div,span,section,article,header,footer,time"
+	DefaultSanitizerAllowedAttributes = "*:class,id,title,lang,dir;a:href,rel,target;img:src,alt,width,height;" +
+		"th:colspan,rowspan,scope;td:colspan,rowspan;ol:start;blockquote:cite;q:cite;time:datetime"
+	DefaultSanitizerAllowedURLSchemes = "http,https,mailto,tel"
+)
+
+// Cache-Control of page responses, see the PAGE_CACHE_CONTROL setting. Drafts are only seen by editors and must be
+// revalidated, so that they see their changes at once.
+const (
+	DefaultPageCacheControl = "public, max-age=60"
+	DraftPageCacheControl   = "private, no-cache"
+)
+
+// PreviewTokenType is the type claim of the tokens of preview links, which only reveal the drafts of a page and are
+// refused as access tokens.
+const PreviewTokenType = "preview"
+
+// MaxIncludeDepth is how deep includes may nest: a block including a block that includes another is two levels deep.
+const MaxIncludeDepth = 5
+
+// ContentBundleVersion is the version of the format of content bundles, increased on incompatible changes.
+const ContentBundleVersion = 1
+
+// Ways a content bundle is imported.
+const (
+	ImportModeUpsert  = "upsert"  // Creates and updates the pages and blocks of the bundle, leaving the others untouched
+	ImportModeReplace = "replace" // Also deletes the blocks and metadata of the bundled pages that the bundle lacks
+)
+
+// Changes made by the import of a content bundle.
+const (
+	BundleChangeCreated = "created"
+	BundleChangeUpdated = "updated"
+	BundleChangeDeleted = "deleted"
+)
+
+// Kinds of records changed by the import of a content bundle.
+const (
+	BundleRecordPage  = "page"
+	BundleRecordBlock = "block"
+)
+
+// SearchSnippetRadius is how many words are kept on each side of the first matched word in the snippets of a search.
+const SearchSnippetRadius = 12
+
+// NewsPageTag marks the pages listed in the news feed.
+const NewsPageTag = "news"
+
+// NewsFeedSize is how many pages the news feed lists, most recently updated first.
+const NewsFeedSize = 20
+
+// Events streamed to the clients following the blocks of a page.
+const (
+	BlockEventCreated   = "created"
+	BlockEventUpdated   = "updated"
+	BlockEventDeleted   = "deleted"
+	BlockEventReset     = "reset"     // Events may have been missed while disconnected, the page should be read again
+	BlockEventHeartbeat = "heartbeat" // Keeps idle connections open, carries no event ID
+)
@@ -0,0 +1,10 @@
+package constants
+
+// Keys used to share request-scoped values through the gin context.
+const (
+	ContextUserIDKey   = "user_id"
+	ContextUserRoleKey = "user_role"
+	ContextLanguageKey = "language"
+	ContextTimezoneKey = "timezone"
+	ContextTenantIDKey = "tenant_id"
+)
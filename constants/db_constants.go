@@ -1,8 +1,34 @@
 package constants
 
 const (
-	DbUsersCollection         = "users"
-	DbContentBlocksCollection = "content_blocks"
-	SortAsc                   = "asc"
-	SortDesc                  = "desc"
+	DbUsersCollection             = "users"
+	DbUserStatusChangesCollection = "user_status_changes"
+	DbSessionsCollection          = "sessions"
+	DbFilesCollection             = "files"
+	DbInvitationsCollection       = "invitations"
+	DbOrganizationsCollection     = "organizations"
+	DbMembershipsCollection       = "memberships"
+	DbContentBlocksCollection     = "content_blocks"
+	DbBlockRevisionsCollection    = "content_block_revisions"
+	DbBlockTypesCollection        = "block_types"
+	DbPagesCollection             = "pages"
+	DbVariablesCollection         = "variables"
+	DbPagePreviewsCollection      = "page_previews"
+	DbSeedHistoryCollection       = "seed_history"
+	SortAsc                       = "asc"
+	SortDesc                      = "desc"
 )
+
+// TenantScopedCollections are the collections whose documents belong to an organization, and are only reachable from
+// operations scoped to it.
+var TenantScopedCollections = []string{
+	DbUsersCollection,
+	DbContentBlocksCollection,
+	DbBlockRevisionsCollection,
+	DbBlockTypesCollection,
+	DbPagesCollection,
+	DbVariablesCollection,
+	DbPagePreviewsCollection,
+	DbInvitationsCollection,
+	DbFilesCollection,
+}
@@ -0,0 +1,16 @@
+package constants
+
+const (
+	AvatarsDirectory   = "avatars"
+	AvatarMaxSizeBytes = 2 << 20 // 2 MB
+	// AvatarMaxRequestBytes leaves room for the multipart headers and boundaries around the avatar.
+	AvatarMaxRequestBytes = AvatarMaxSizeBytes + 64<<10
+)
+
+// AvatarAllowedMimeTypes maps every accepted avatar MIME type to the extension used to store it.
+var AvatarAllowedMimeTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
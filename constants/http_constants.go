@@ -0,0 +1,7 @@
+package constants
+
+// Custom HTTP headers understood by the API.
+const (
+	HeaderTimezone = "X-Timezone"
+	HeaderTenant   = "X-Tenant"
+)
@@ -0,0 +1,11 @@
+package constants
+
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusRevoked  = "revoked"
+)
+
+// InvitationTokenType is the type claim of the tokens sent in invitation links, which only accept an invitation and
+// are refused as access tokens.
+const InvitationTokenType = "invitation"
@@ -19,6 +19,10 @@ const (
 	ErrUnprocessableEntity = "unprocessable_entity"
 	ErrConflict            = "conflict"
 	ErrValidationFailed    = "validation_failed"
+	ErrVersionMismatch     = "err_version_mismatch"
+	ErrIfMatchRequired     = "err_if_match_required"
+	ErrInvalidIfMatch      = "err_invalid_if_match"
+	ErrIfMatchMultipleTags = "err_if_match_multiple_tags"
 
 	// "2-------------------------": "2-------------------------",
 	// "---------2.General--------": "---------2.General--------",
@@ -41,8 +45,21 @@ const (
 	// "-------3.Resources--------": "-------3.Resources--------",
 	// "-------------------------3": "-------------------------3",
 
-	MsgUserResource         = "user_resource"
-	MsgContentBlockResource = "content_block_resource"
+	MsgUserResource              = "user_resource"
+	MsgContentBlockResource      = "content_block_resource"
+	MsgUserStatusHistoryResource = "user_status_history_resource"
+	MsgAvatarResource            = "avatar_resource"
+	MsgFileResource              = "file_resource"
+	MsgUserDataExportResource    = "user_data_export_resource"
+	MsgInvitationResource        = "invitation_resource"
+	MsgOrganizationResource      = "organization_resource"
+	MsgMembershipResource        = "membership_resource"
+	MsgBlockRevisionResource     = "block_revision_resource"
+	MsgBlockTypeResource         = "block_type_resource"
+	MsgPageResource              = "page_resource"
+	MsgContentBundleResource     = "content_bundle_resource"
+	MsgVariableResource          = "variable_resource"
+	MsgPreviewResource           = "preview_resource"
 
 	// "4-------------------------": "4-------------------------",
 	// "-----------4.Auth---------": "-----------4.Auth---------",
@@ -54,4 +71,82 @@ const (
 	MsgUserLoggedIn       = "user_logged_in"
 	MsgLoginSuccessful    = "login_successful"
 	MsgEmailVerified      = "email_verified"
+	ErrEmailAlreadyUsed   = "err_email_already_used"
+
+	ErrMissingToken   = "err_missing_token"
+	ErrInvalidToken   = "err_invalid_token"
+	ErrUserBlocked    = "err_user_blocked"
+	ErrRoleNotAllowed = "err_role_not_allowed"
+
+	// "5-------------------------": "5-------------------------",
+	// "----------5.Users---------": "----------5.Users---------",
+	// "-------------------------5": "-------------------------5",
+
+	MsgUserBlocked                = "user_blocked"
+	MsgUserUnblocked              = "user_unblocked"
+	MsgUserActivated              = "user_activated"
+	ErrInvalidStatusTransition    = "err_invalid_status_transition"
+	ErrUserStatusChangeNotAllowed = "err_user_status_change_not_allowed"
+	MsgUserErased                 = "user_erased"
+	ErrUserErased                 = "err_user_erased"
+
+	ErrAvatarTooLarge      = "err_avatar_too_large"
+	ErrAvatarInvalidType   = "err_avatar_invalid_type"
+	ErrAvatarNotFound      = "err_avatar_not_found"
+	ErrAvatarUploadMissing = "err_avatar_upload_missing"
+
+	MsgPreferencesResource = "preferences_resource"
+
+	MsgInvitationSent         = "invitation_sent"
+	MsgInvitationResent       = "invitation_resent"
+	MsgInvitationRevoked      = "invitation_revoked"
+	MsgInvitationAccepted     = "invitation_accepted"
+	ErrInvalidInvitationToken = "err_invalid_invitation_token"
+	ErrInvitationExpired      = "err_invitation_expired"
+	ErrInvitationNotPending   = "err_invitation_not_pending"
+
+	ErrUnknownTenant                = "err_unknown_tenant"
+	ErrNotTenantMember              = "err_not_tenant_member"
+	ErrTenantRequired               = "err_tenant_required"
+	ErrTenantNotAllowed             = "err_tenant_not_allowed"
+	ErrTenantAuthenticationRequired = "err_tenant_authentication_required"
+	ErrSlugAlreadyUsed              = "err_slug_already_used"
+
+	MsgBlockPublished           = "block_published"
+	MsgBlockPublishScheduled    = "block_publish_scheduled"
+	MsgBlockUnpublished         = "block_unpublished"
+	MsgBlockUnpublishScheduled  = "block_unpublish_scheduled"
+	MsgBundleImported           = "bundle_imported"
+	MsgBundleImportChecked      = "bundle_import_checked"
+	ErrMarkupNotAllowed         = "err_markup_not_allowed"
+	ErrInvalidJSONSchema        = "err_invalid_json_schema"
+	ErrUnknownBlockType         = "err_unknown_block_type"
+	ErrBlockTypeInUse           = "err_block_type_in_use"
+	ErrSchemaRejectsBlocks      = "err_schema_rejects_blocks"
+	ErrContentNotJSON           = "err_content_not_json"
+	ErrPageSlugAlreadyUsed      = "err_page_slug_already_used"
+	ErrSectionsMismatch         = "err_sections_mismatch"
+	ErrBundleVersionUnsupported = "err_bundle_version_unsupported"
+	ErrDuplicatedInBundle       = "err_duplicated_in_bundle"
+	ErrInvalidInclude           = "err_invalid_include"
+	ErrIncludeCycle             = "err_include_cycle"
+	ErrIncludeTooDeep           = "err_include_too_deep"
+	ErrInvalidPreviewToken      = "err_invalid_preview_token"
+	ErrPreviewExpired           = "err_preview_expired"
+	ErrPreviewExpiryInPast      = "err_preview_expiry_in_past"
+	ErrFileInUse                = "err_file_in_use"
+
+	// "6-------------------------": "6-------------------------",
+	// "----------6.Emails--------": "----------6.Emails--------",
+	// "-------------------------6": "-------------------------6",
+
+	EmailVerificationSubject  = "email_verification_subject"
+	EmailVerificationBody     = "email_verification_body"
+	EmailStatusChangedSubject = "email_status_changed_subject"
+	EmailStatusChangedBody    = "email_status_changed_body"
+	EmailInvitationSubject    = "email_invitation_subject"
+	EmailInvitationBody       = "email_invitation_body"
+
+	// UserStatusPrefix prefixes a user status to build the key of its localized name, e.g. "user_status_blocked".
+	UserStatusPrefix = "user_status_"
 )
@@ -4,4 +4,17 @@ const (
 	UserStatusActivated = "activated"
 	UserStatusPending   = "pending"
 	UserStatusBlocked   = "blocked"
+	UserStatusErased    = "erased"
 )
+
+// ErasedUserEmailDomain is the domain of the placeholder email given to users whose personal data was erased.
+const ErasedUserEmailDomain = "erased.invalid"
+
+const (
+	UserRoleAdmin     = "admin"
+	UserRoleModerator = "moderator"
+	UserRoleUser      = "user"
+)
+
+// EditorRoles lists the roles of the users who edit content, and so may read its drafts.
+var EditorRoles = []string{UserRoleAdmin, UserRoleModerator}
@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// BlockType describes, as a JSON Schema, the structure of the JSON content of the blocks of that type, e.g. hero banners
+// or FAQs.
+type BlockType struct {
+	ID          primitive.ObjectID  `bson:"_id" json:"id"`
+	TenantID    *primitive.ObjectID `bson:"tenant_id,omitempty" json:"tenant_id,omitempty" index:"tenant_name_idx,unique"`
+	Name        string              `bson:"name" json:"name" validate:"required" index:"tenant_name_idx,unique"`
+	Description string              `bson:"description" json:"description"`
+	Schema      string              `bson:"schema" json:"schema" validate:"required"` // Stored as text, since schema keywords start with $
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// SetTenantID sets the organization the block type belongs to, nil for the types of the platform itself.
+func (s *BlockType) SetTenantID(tenantID *primitive.ObjectID) {
+	s.TenantID = tenantID
+}
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// ContentBlockRevision is an immutable copy of the content of a block, written on every create and update. Revisions
+// are numbered after the version of the block they produced.
+type ContentBlockRevision struct {
+	ID           primitive.ObjectID  `bson:"_id" json:"id"`
+	TenantID     *primitive.ObjectID `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	BlockID      primitive.ObjectID  `bson:"block_id" json:"block_id" index:"block_revision_idx,unique"`
+	Revision     int64               `bson:"revision" json:"revision" index:"block_revision_idx,unique,desc"`
+	Content      string              `bson:"content" json:"content"`
+	Format       string              `bson:"format" json:"format"`
+	AuthorID     string              `bson:"author_id" json:"author_id"`
+	RestoredFrom *int64              `bson:"restored_from,omitempty" json:"restored_from,omitempty"` // Revision rolled back to, if any
+	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// SetTenantID sets the organization the revised block belongs to.
+func (s *ContentBlockRevision) SetTenantID(tenantID *primitive.ObjectID) {
+	s.TenantID = tenantID
+}
@@ -1,23 +1,56 @@
 package entities
 
 import (
+	"company-name/constants"
+	"company-name/pkg/localization"
 	"company-name/pkg/validators"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type BlockKey struct {
-	Page    string `json:"page" validate:"required" index:"page_section_idx,unique" bson:"page"`
-	Section string `json:"section" validate:"required" index:"page_section_idx,unique" bson:"section"`
+	Page    string `json:"page" validate:"required" index:"tenant_page_section_locale_idx,unique;search_idx,text,weight=5" bson:"page"`
+	Section string `json:"section" validate:"required" index:"tenant_page_section_locale_idx,unique;search_idx,text,weight=5" bson:"section"`
+	Locale  string `json:"locale" validate:"required" index:"tenant_page_section_locale_idx,unique" bson:"locale"` // Missing on blocks written before variants existed, which are in the default language
+}
+
+// NewBlockKey builds the key of a variant of a block, in the default language when no locale is given.
+func NewBlockKey(page, section, locale string) BlockKey {
+	locale = strings.ToLower(locale)
+	if locale == "" {
+		locale = localization.DefaultLang
+	}
+	return BlockKey{Page: page, Section: section, Locale: locale}
 }
 
 type ContentBlocks struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Key       BlockKey           `bson:"key" json:"key"`                             // Unique key for the content block
-	Content   string             `bson:"content" json:"content" validate:"required"` // Content of the block Can be in HTML or Markdown or Plain Text
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	TenantID  *primitive.ObjectID `bson:"tenant_id,omitempty" json:"tenant_id,omitempty" index:"tenant_page_section_locale_idx,unique;tenant_file_ids_idx"`
+	Key       BlockKey            `bson:"key" json:"key"`                             // Unique key for the variant of the content block, within its tenant
+	Content   string              `bson:"content" json:"content" validate:"required"` // Draft content of the block, written in Format
+	Version   int64               `bson:"version" json:"version"`                     // Incremented on every write, exposed as the ETag
+	Format    string              `bson:"format" json:"format" validate:"required,oneof=html markdown text json"`
+	Type      string              `bson:"type,omitempty" json:"type,omitempty"` // Block type whose schema the JSON content satisfies, empty for untyped blocks
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time           `bson:"updated_at" json:"updated_at"`
+
+	Status           string     `bson:"status,omitempty" json:"status"`                       // Empty for blocks written before drafts existed, see FillLegacyFields
+	PublishedContent string     `bson:"published_content" json:"published_content"`           // Content visible to the public while published
+	PublishedFormat  string     `bson:"published_format" json:"published_format"`             // Format of PublishedContent
+	PublishedAt      *time.Time `bson:"published_at,omitempty" json:"published_at,omitempty"` // Last time the block was published
+	PublishAt        *time.Time `bson:"publish_at,omitempty" json:"publish_at,omitempty" index:"publish_at_idx"`
+	UnpublishAt      *time.Time `bson:"unpublish_at,omitempty" json:"unpublish_at,omitempty" index:"unpublish_at_idx"`
+
+	// Derived from the draft and published content by the repository. Written even when empty so that updates clear them
+	FileIDs    []primitive.ObjectID `bson:"file_ids" json:"file_ids,omitempty" index:"tenant_file_ids_idx"` // Stored files the content refers to
+	SearchText string               `bson:"search_text" json:"-" index:"search_idx,text"`                   // Content stripped of its markup, see searchText
+}
+
+// SetTenantID sets the organization the block belongs to, nil for the content of the platform itself.
+func (s *ContentBlocks) SetTenantID(tenantID *primitive.ObjectID) {
+	s.TenantID = tenantID
 }
 
 func (s *ContentBlocks) Validate(validator validators.IValidator) error {
@@ -26,3 +59,53 @@ func (s *ContentBlocks) Validate(validator validators.IValidator) error {
 	}
 	return nil
 }
+
+// FillLegacyFields completes the blocks written before variants, formats and drafts existed: they are in the default
+// language, their content was served as is, like HTML, and they were live as soon as they were written, so they are
+// treated as published with their current content.
+func (s *ContentBlocks) FillLegacyFields() {
+	if s.Key.Locale == "" {
+		s.Key.Locale = localization.DefaultLang
+	}
+	if s.Format == "" {
+		s.Format = constants.BlockFormatHTML
+	}
+	if s.Status == "" {
+		s.Status = constants.BlockStatusPublished
+		s.PublishedContent = s.Content
+		publishedAt := s.UpdatedAt
+		s.PublishedAt = &publishedAt
+	}
+	if s.IsPublished() && s.PublishedFormat == "" {
+		s.PublishedFormat = constants.BlockFormatHTML
+	}
+}
+
+func (s *ContentBlocks) IsPublished() bool {
+	return s.Status == constants.BlockStatusPublished
+}
+
+// Publish makes the draft content visible to the public and clears the scheduled publishing, if any.
+func (s *ContentBlocks) Publish(now time.Time) {
+	s.Status = constants.BlockStatusPublished
+	s.PublishedContent = s.Content
+	s.PublishedFormat = s.Format
+	s.PublishedAt = &now
+	s.PublishAt = nil
+}
+
+// Unpublish hides the block from the public and clears the scheduled unpublishing, if any. The draft is kept.
+func (s *ContentBlocks) Unpublish() {
+	s.Status = constants.BlockStatusDraft
+	s.PublishedContent = ""
+	s.PublishedFormat = ""
+	s.UnpublishAt = nil
+}
+
+// PublishedView returns a copy of the block showing its published content as its content, for public reads.
+func (s *ContentBlocks) PublishedView() *ContentBlocks {
+	view := *s
+	view.Content = s.PublishedContent
+	view.Format = s.PublishedFormat
+	return &view
+}
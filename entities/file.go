@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"path"
+	"time"
+)
+
+// File holds the metadata of a file stored through the file service.
+type File struct {
+	ID           primitive.ObjectID  `bson:"_id" json:"id"`
+	TenantID     *primitive.ObjectID `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	OwnerID      *primitive.ObjectID `bson:"owner_id,omitempty" json:"owner_id,omitempty"`
+	Path         string              `bson:"path" json:"path"`
+	OriginalName string              `bson:"original_name" json:"original_name"`
+	MimeType     string              `bson:"mime_type" json:"mime_type"`
+	Size         int64               `bson:"size" json:"size"`
+	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// SetTenantID sets the organization the file was uploaded in, nil for files of the platform itself.
+func (f *File) SetTenantID(tenantID *primitive.ObjectID) {
+	f.TenantID = tenantID
+}
+
+// Anonymize detaches the file from its owner and replaces the name it was uploaded with, which may reveal personal
+// data, by the name it is stored under.
+func (f *File) Anonymize() {
+	f.OwnerID = nil
+	f.OriginalName = path.Base(f.Path)
+}
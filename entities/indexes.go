@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"company-name/constants"
+	"company-name/pkg/database"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Indexes returns every index the application expects: the ones declared with `index` tags on the entities, followed
+// by the ones that cannot be expressed with tags.
+func Indexes() []database.IndexDefinition {
+	return slices.Concat(
+		database.IndexesFromStruct(constants.DbUsersCollection, User{}),
+		database.IndexesFromStruct(constants.DbContentBlocksCollection, ContentBlocks{}),
+		database.IndexesFromStruct(constants.DbBlockRevisionsCollection, ContentBlockRevision{}),
+		database.IndexesFromStruct(constants.DbBlockTypesCollection, BlockType{}),
+		database.IndexesFromStruct(constants.DbPagesCollection, Page{}),
+		database.IndexesFromStruct(constants.DbVariablesCollection, Variable{}),
+		database.IndexesFromStruct(constants.DbPagePreviewsCollection, PagePreview{}),
+		database.IndexesFromStruct(constants.DbSeedHistoryCollection, SeedHistory{}),
+		database.IndexesFromStruct(constants.DbInvitationsCollection, Invitation{}),
+		database.IndexesFromStruct(constants.DbOrganizationsCollection, Organization{}),
+		database.IndexesFromStruct(constants.DbMembershipsCollection, Membership{}),
+		explicitIndexes,
+	)
+}
+
+var explicitIndexes = []database.IndexDefinition{
+	{
+		Collection: constants.DbUserStatusChangesCollection,
+		Name:       "user_created_at_idx",
+		Keys:       bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}},
+	},
+	{
+		Collection: constants.DbSessionsCollection,
+		Name:       "user_idx",
+		Keys:       bson.D{{Key: "user_id", Value: 1}},
+	},
+	{
+		Collection: constants.DbFilesCollection,
+		Name:       "owner_idx",
+		Keys:       bson.D{{Key: "owner_id", Value: 1}},
+	},
+}
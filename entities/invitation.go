@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"company-name/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// Invitation is sent by an administrator to let someone set the password of the pending account created for them.
+// Only the latest link sent is valid: resending replaces the token id.
+type Invitation struct {
+	ID         primitive.ObjectID  `bson:"_id" json:"id"`
+	TenantID   *primitive.ObjectID `bson:"tenant_id,omitempty" json:"tenant_id,omitempty" index:"tenant_status_created_at_idx"`
+	UserID     primitive.ObjectID  `bson:"user_id" json:"user_id" index:"user_idx"`
+	Email      string              `bson:"email" json:"email"`
+	InvitedBy  string              `bson:"invited_by" json:"invited_by"`
+	TokenID    string              `bson:"token_id" json:"-"`
+	Status     string              `bson:"status" json:"status" index:"tenant_status_created_at_idx"`
+	ExpiresAt  time.Time           `bson:"expires_at" json:"expires_at"`
+	SentAt     time.Time           `bson:"sent_at" json:"sent_at"`
+	SendCount  int                 `bson:"send_count" json:"send_count"`
+	AcceptedAt *time.Time          `bson:"accepted_at,omitempty" json:"accepted_at,omitempty"`
+	RevokedAt  *time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at" index:"tenant_status_created_at_idx,desc"`
+	UpdatedAt  time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// SetTenantID sets the organization the invitee joins, nil for users of the platform itself.
+func (s *Invitation) SetTenantID(tenantID *primitive.ObjectID) {
+	s.TenantID = tenantID
+}
+
+// IsPending reports whether the invitation was neither accepted nor revoked, regardless of its expiry.
+func (s *Invitation) IsPending() bool {
+	return s.Status == constants.InvitationStatusPending
+}
+
+// IsExpired reports whether the link of the invitation can no longer be used at the given time.
+func (s *Invitation) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
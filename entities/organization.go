@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// Organization is a client company served by the platform. Its ID is the tenant that scopes the data of its users.
+type Organization struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	Name      string             `bson:"name" json:"name" validate:"required"`
+	Slug      string             `bson:"slug" json:"slug" validate:"required" index:"slug_idx,unique"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Membership grants a user a role within an organization, which replaces the user's own role while acting as that
+// tenant.
+type Membership struct {
+	ID             primitive.ObjectID `bson:"_id" json:"id"`
+	OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id" index:"organization_user_idx,unique"`
+	UserID         primitive.ObjectID `bson:"user_id" json:"user_id" index:"organization_user_idx,unique;user_idx"`
+	Role           string             `bson:"role" json:"role" validate:"required"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Page holds the metadata of a page and the order of its sections. The blocks of the page refer to it by its slug, the
+// page of their key.
+type Page struct {
+	ID              primitive.ObjectID  `bson:"_id" json:"id"`
+	TenantID        *primitive.ObjectID `bson:"tenant_id,omitempty" json:"tenant_id,omitempty" index:"tenant_slug_idx,unique;tenant_tags_idx"`
+	Slug            string              `bson:"slug" json:"slug" validate:"required" index:"tenant_slug_idx,unique"`
+	Title           string              `bson:"title" json:"title" validate:"required,max=200"`
+	MetaDescription string              `bson:"meta_description" json:"meta_description" validate:"max=300"`
+	OGImage         string              `bson:"og_image" json:"og_image" validate:"omitempty,url"`
+	Sections        []string            `bson:"sections" json:"sections" validate:"unique,dive,required"`                        // Sections of the page in display order
+	Tags            []string            `bson:"tags" json:"tags" validate:"unique,dive,required,max=50" index:"tenant_tags_idx"` // Lowercase, e.g. constants.NewsPageTag for the pages of the news feed
+	CreatedAt       time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// SetTenantID sets the organization the page belongs to, nil for the pages of the platform itself.
+func (s *Page) SetTenantID(tenantID *primitive.ObjectID) {
+	s.TenantID = tenantID
+}
+
+// NormalizeTags lowercases and trims the tags of a page and drops the ones repeated once normalized, never returning
+// nil so that pages without tags list none. Every write of tags goes through it, so that tags differing only in case
+// are the same tag.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
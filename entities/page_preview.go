@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// PagePreview lets whoever holds its link read the drafts of a page without an account, until it expires or is
+// revoked. The token of the link is derived from the preview, so it is not stored.
+type PagePreview struct {
+	ID        primitive.ObjectID  `bson:"_id" json:"id"`
+	TenantID  *primitive.ObjectID `bson:"tenant_id,omitempty" json:"tenant_id,omitempty" index:"tenant_page_created_at_idx"`
+	Page      string              `bson:"page" json:"page" index:"tenant_page_created_at_idx"`
+	Note      string              `bson:"note" json:"note"` // Who the link was shared with, for editors
+	CreatedBy string              `bson:"created_by" json:"created_by"`
+	ExpiresAt time.Time           `bson:"expires_at" json:"expires_at"`
+	RevokedAt *time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at" index:"tenant_page_created_at_idx,desc"`
+}
+
+// SetTenantID sets the organization the previewed page belongs to, nil for the pages of the platform itself.
+func (s *PagePreview) SetTenantID(tenantID *primitive.ObjectID) {
+	s.TenantID = tenantID
+}
+
+// IsRevoked reports whether the link of the preview was revoked.
+func (s *PagePreview) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// IsExpired reports whether the link of the preview can no longer be used at the given time.
+func (s *PagePreview) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// SeedHistory records a version of a fixture applied by the seeder, so that it is not applied again.
+type SeedHistory struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	Fixture     string             `bson:"fixture" json:"fixture" index:"fixture_version_idx"`
+	Version     int                `bson:"version" json:"version" index:"fixture_version_idx,desc"`
+	Checksum    string             `bson:"checksum" json:"checksum"` // SHA-256 of the fixture file, to notice changes made without a new version
+	Environment string             `bson:"environment" json:"environment"`
+	AppliedAt   time.Time          `bson:"applied_at" json:"applied_at"`
+}
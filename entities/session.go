@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// Session records a successful login and the token it issued.
+type Session struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	IPAddress string             `bson:"ip_address" json:"ip_address"`
+	UserAgent string             `bson:"user_agent" json:"user_agent"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+}
@@ -1,21 +1,37 @@
 package entities
 
 import (
+	"company-name/constants"
 	"company-name/pkg/validators"
+	"fmt"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"time"
 )
 
+// userStatusTransitions lists, for every status, the statuses a user may be moved to.
+var userStatusTransitions = map[string][]string{
+	constants.UserStatusPending:   {constants.UserStatusActivated, constants.UserStatusBlocked, constants.UserStatusErased},
+	constants.UserStatusActivated: {constants.UserStatusBlocked, constants.UserStatusErased},
+	constants.UserStatusBlocked:   {constants.UserStatusActivated, constants.UserStatusErased},
+}
+
 type User struct {
-	ID             primitive.ObjectID `bson:"_id" json:"id"`
-	Email          string             `bson:"email" json:"email" validate:"required"`
-	HashedPassword string             `bson:"hashed_password" json:"hashed_password" validate:"required"`
-	FirstName      string             `bson:"first_name" json:"first_name" validate:"required"`
-	LastName       string             `bson:"last_name" json:"last_name" validate:"required"`
-	PhoneNumber    string             `bson:"phone_number" json:"phone_number" validate:"required"`
-	Status         string             `bson:"status" json:"status" validate:"required" example:"pending"`
-	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+	ID                primitive.ObjectID  `bson:"_id" json:"id"`
+	TenantID          *primitive.ObjectID `bson:"tenant_id,omitempty" json:"tenant_id,omitempty" index:"tenant_email_idx,unique"`
+	Email             string              `bson:"email" json:"email" validate:"required" index:"tenant_email_idx,unique;search_idx,text,weight=5"`
+	HashedPassword    string              `bson:"hashed_password" json:"hashed_password" validate:"required"`
+	FirstName         string              `bson:"first_name" json:"first_name" validate:"required" index:"search_idx,text,weight=10"`
+	LastName          string              `bson:"last_name" json:"last_name" validate:"required" index:"search_idx,text,weight=10"`
+	PhoneNumber       string              `bson:"phone_number" json:"phone_number" validate:"required" index:"search_idx,text"`
+	AvatarURL         string              `bson:"avatar_url" json:"avatar_url"`
+	PreferredLanguage string              `bson:"preferred_language" json:"preferred_language"`
+	Timezone          string              `bson:"timezone" json:"timezone"`
+	Role              string              `bson:"role" json:"role" example:"user"`
+	Status            string              `bson:"status" json:"status" validate:"required" example:"pending"`
+	ErasedAt          *time.Time          `bson:"erased_at,omitempty" json:"erased_at,omitempty"`
+	Version           int64               `bson:"version" json:"version"` // Incremented on every write, exposed as the ETag
+	CreatedAt         time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `bson:"updated_at" json:"updated_at"`
 }
 
 func (s *User) Validate(validator validators.IValidator) error {
@@ -24,3 +40,61 @@ func (s *User) Validate(validator validators.IValidator) error {
 	}
 	return nil
 }
+
+// SetTenantID sets the organization the user belongs to, nil for users of the platform itself.
+func (s *User) SetTenantID(tenantID *primitive.ObjectID) {
+	s.TenantID = tenantID
+}
+
+// FullName returns the user's first and last name separated by a space.
+func (s *User) FullName() string {
+	return s.FirstName + " " + s.LastName
+}
+
+// HasAvatar reports whether the user has uploaded an avatar.
+func (s *User) HasAvatar() bool {
+	return s.AvatarURL != ""
+}
+
+// IsBlocked reports whether the user has been blocked by an administrator.
+func (s *User) IsBlocked() bool {
+	return s.Status == constants.UserStatusBlocked
+}
+
+// IsErased reports whether the personal data of the user was erased.
+func (s *User) IsErased() bool {
+	return s.Status == constants.UserStatusErased
+}
+
+// Anonymize replaces every piece of personal data of the user with placeholders while keeping its ID, so that
+// documents referencing the user stay valid.
+func (s *User) Anonymize(erasedAt time.Time) {
+	s.Email = fmt.Sprintf("%s@%s", s.ID.Hex(), constants.ErasedUserEmailDomain)
+	s.HashedPassword = ""
+	s.FirstName = ""
+	s.LastName = ""
+	s.PhoneNumber = ""
+	s.AvatarURL = ""
+	s.PreferredLanguage = ""
+	s.Timezone = ""
+	s.Status = constants.UserStatusErased
+	s.ErasedAt = &erasedAt
+}
+
+// CurrentStatus returns the user's status, treating users created without one as pending.
+func (s *User) CurrentStatus() string {
+	if s.Status == "" {
+		return constants.UserStatusPending
+	}
+	return s.Status
+}
+
+// CanTransitionTo reports whether the user's current status may be changed to the given status.
+func (s *User) CanTransitionTo(status string) bool {
+	for _, allowed := range userStatusTransitions[s.CurrentStatus()] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
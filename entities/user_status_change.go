@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// UserStatusChange is an entry of a user's status history, written every time an administrator changes the status.
+type UserStatusChange struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
+	FromStatus string             `bson:"from_status" json:"from_status"`
+	ToStatus   string             `bson:"to_status" json:"to_status"`
+	Reason     string             `bson:"reason" json:"reason"`
+	ChangedBy  string             `bson:"changed_by" json:"changed_by"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// Variable is a named value shared by the content blocks, e.g. a phone number, inserted wherever their content holds
+// {{var "name"}}.
+type Variable struct {
+	ID          primitive.ObjectID  `bson:"_id" json:"id"`
+	TenantID    *primitive.ObjectID `bson:"tenant_id,omitempty" json:"tenant_id,omitempty" index:"tenant_name_idx,unique"`
+	Name        string              `bson:"name" json:"name" validate:"required" index:"tenant_name_idx,unique"`
+	Value       string              `bson:"value" json:"value"`
+	Description string              `bson:"description" json:"description"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// SetTenantID sets the organization the variable belongs to, nil for the variables of the platform itself.
+func (s *Variable) SetTenantID(tenantID *primitive.ObjectID) {
+	s.TenantID = tenantID
+}
@@ -19,6 +19,7 @@ type IAuthRepository interface {
 	GetUserById(ctx context.Context, id string) (*entities.User, error)
 	UpdatePassword(ctx context.Context, id string, hashedPassword string) error
 	UpdateUser(ctx context.Context, user *entities.User) error
+	CreateSession(ctx context.Context, session *entities.Session) error
 }
 
 type Repository struct {
@@ -73,7 +74,10 @@ func (r *Repository) UpdatePassword(ctx context.Context, id string, hashedPasswo
 		return errors.New("invalid user ID")
 	}
 
-	update := bson.M{"$set": bson.M{"password": hashedPassword, "updatedAt": time.Now()}}
+	update := bson.M{
+		"$set": bson.M{"password": hashedPassword, "updatedAt": time.Now()},
+		"$inc": bson.M{"version": 1},
+	}
 	filter := bson.M{"_id": objectId}
 
 	err = r.db.Update(ctx, constants.DbUsersCollection, filter, update)
@@ -85,15 +89,25 @@ func (r *Repository) UpdatePassword(ctx context.Context, id string, hashedPasswo
 
 func (r *Repository) UpdateUser(ctx context.Context, user *entities.User) error {
 	user.UpdatedAt = time.Now()
+	expectedVersion := user.Version
+	user.Version++
 
-	filter := bson.M{"_id": user.ID}
+	filter := bson.M{"_id": user.ID, "version": database.VersionFilter(expectedVersion)}
 	update := bson.M{
 		"$set": user,
 	}
 
 	if err := r.db.Update(ctx, constants.DbUsersCollection, filter, update); err != nil {
+		user.Version = expectedVersion
 		return err
 	}
 
 	return nil
 }
+
+func (r *Repository) CreateSession(ctx context.Context, session *entities.Session) error {
+	if err := r.db.Create(ctx, constants.DbSessionsCollection, session); err != nil {
+		return err
+	}
+	return nil
+}
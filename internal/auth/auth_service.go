@@ -3,11 +3,14 @@ package auth
 import (
 	"company-name/configs"
 	"company-name/constants"
+	"company-name/constants/msgkey"
 	"company-name/entities"
 	"company-name/internal/auth/dtos"
+	"company-name/pkg/database"
 	"company-name/pkg/email"
 	errors2 "company-name/pkg/errors"
 	"company-name/pkg/hasher"
+	"company-name/pkg/idgenerator"
 	"company-name/pkg/jwttoken"
 	"company-name/pkg/localization"
 	"company-name/pkg/validators"
@@ -15,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v4"
+	"time"
 )
 
 type IAuthService interface {
@@ -55,11 +59,30 @@ func (s *Service) GetToken(ctx context.Context, req *dtos.LoginRequest) (*dtos.L
 		return nil, err
 	}
 
-	token, err := s.generateToken(user)
+	if user.IsBlocked() {
+		return nil, errors2.ForbiddenM(msgkey.ErrUserBlocked, nil)
+	}
+
+	issuedAt := time.Now()
+	expiresAt := issuedAt.Add(time.Duration(s.config.JWT.Expiration) * time.Millisecond)
+
+	token, err := s.generateToken(ctx, user, expiresAt)
 	if err != nil {
 		return nil, errors.New(localization.L("error_token_generation"))
 	}
 
+	session := &entities.Session{
+		ID:        idgenerator.GenerateID(),
+		UserID:    user.ID,
+		IPAddress: req.IPAddress,
+		UserAgent: req.UserAgent,
+		CreatedAt: issuedAt,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repository.CreateSession(ctx, session); err != nil {
+		return nil, errors2.InternalServerError(err)
+	}
+
 	response := &dtos.LoginResponse{
 		Token:                    token,
 		ExpirationInMilliseconds: s.config.JWT.Expiration,
@@ -70,12 +93,8 @@ func (s *Service) GetToken(ctx context.Context, req *dtos.LoginRequest) (*dtos.L
 
 func (s *Service) Register(ctx context.Context, req *dtos.RegisterRequest) (*dtos.RegisterResponse, error) {
 
-	userExisted, err := s.repository.GetUserByEmail(ctx, req.Email)
-	//if err != nil {
-	//	return nil, errors2.BadRequestM("error_invalid_credentials", err)
-	//}
-	if err != nil && userExisted != nil {
-		return nil, errors2.BadRequestM("error_email_already_used", err)
+	if _, err := s.repository.GetUserByEmail(ctx, req.Email); err == nil {
+		return nil, errors2.ConflictM(msgkey.ErrEmailAlreadyUsed, nil)
 	}
 
 	hashedPassword, err := hasher.HashPassword(req.Password)
@@ -89,6 +108,9 @@ func (s *Service) Register(ctx context.Context, req *dtos.RegisterRequest) (*dto
 	// Persist the user in the database
 	user, err := s.repository.CreateUser(ctx, &createdUser)
 	if err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, errors2.ConflictM(msgkey.ErrEmailAlreadyUsed, err)
+		}
 		return nil, errors2.InternalServerErrorM("error_user_creation", err)
 	}
 
@@ -106,12 +128,20 @@ func (s *Service) VerifyEmail(ctx context.Context, req *dtos.VerifyEmailRequest)
 		return errors2.BadRequestM("error_invalid_token", err)
 	}
 
+	// The link carries no tenant, so the user is looked up across tenants
+	ctx = database.WithoutTenantScope(ctx)
+
 	// Fetch user by ID
 	user, err := s.repository.GetUserById(ctx, claims["sub"].(string))
 	if err != nil {
 		return errors2.BadRequestM("error_invalid_user", err)
 	}
 
+	// Only a pending user is activated by verifying its email; blocked or erased users stay as they are
+	if user.CurrentStatus() != constants.UserStatusPending || !user.CanTransitionTo(constants.UserStatusActivated) {
+		return errors2.UnprocessableEntityM(localization.L(msgkey.ErrInvalidStatusTransition, user.CurrentStatus(), constants.UserStatusActivated), nil)
+	}
+
 	// Update the user's email verification status
 	user.Status = constants.UserStatusActivated
 	if err := s.repository.UpdateUser(ctx, user); err != nil {
@@ -121,14 +151,18 @@ func (s *Service) VerifyEmail(ctx context.Context, req *dtos.VerifyEmailRequest)
 	return nil
 }
 
-func (s *Service) generateToken(user *entities.User) (string, error) {
-	jwtExp := s.config.JWT.Expiration
+// generateToken signs an access token for the user. Tokens obtained through an organization are bound to it, so that
+// later requests are scoped to it without naming it again.
+func (s *Service) generateToken(ctx context.Context, user *entities.User, expiresAt time.Time) (string, error) {
 	jwtSec := s.config.JWT.Secret
 
 	claims := jwt.MapClaims{
-		"sub":   user.ID,
+		"sub":   user.ID.Hex(),
 		"email": user.Email,
-		"exp":   jwtExp,
+		"exp":   jwt.NewNumericDate(expiresAt),
+	}
+	if tenantID, ok := database.TenantFromContext(ctx); ok {
+		claims[constants.ContextTenantIDKey] = tenantID.Hex()
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
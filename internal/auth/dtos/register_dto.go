@@ -23,7 +23,9 @@ func (d *RegisterRequest) ToEntity(hashedPassword string) entities.User {
 		Email:          d.Email,
 		HashedPassword: hashedPassword,
 		PhoneNumber:    d.PhoneNumber,
+		Role:           constants.UserRoleUser,
 		Status:         constants.UserStatusPending, // Default status during registration
+		Version:        1,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
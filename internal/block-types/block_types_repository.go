@@ -0,0 +1,84 @@
+package blocktypes
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/database"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// IBlockTypeRepository defines the interface for the types of structured content blocks
+type IBlockTypeRepository interface {
+	Create(ctx context.Context, blockType *entities.BlockType) error
+	Update(ctx context.Context, blockType *entities.BlockType) error
+	Delete(ctx context.Context, name string) error
+	FindByName(ctx context.Context, name string) (*entities.BlockType, error)
+	FindAll(ctx context.Context) ([]*entities.BlockType, error)
+	CountBlocksOfType(ctx context.Context, name string) (int64, error)
+	FindBlocksOfType(ctx context.Context, name string) ([]*entities.ContentBlocks, error)
+}
+
+type Repository struct {
+	db database.IDatabase
+}
+
+// NewBlockTypeRepository initializes a new block type repository
+func NewBlockTypeRepository(db database.IDatabase) IBlockTypeRepository {
+	return &Repository{db: db}
+}
+
+// Create adds a new block type
+func (r *Repository) Create(ctx context.Context, blockType *entities.BlockType) error {
+	return r.db.Create(ctx, constants.DbBlockTypesCollection, blockType)
+}
+
+// Update persists the description and schema of an existing block type
+func (r *Repository) Update(ctx context.Context, blockType *entities.BlockType) error {
+	filter := bson.M{"_id": blockType.ID}
+	update := bson.M{"$set": bson.M{
+		"description": blockType.Description,
+		"schema":      blockType.Schema,
+		"updated_at":  blockType.UpdatedAt,
+	}}
+
+	return r.db.Update(ctx, constants.DbBlockTypesCollection, filter, update)
+}
+
+// Delete removes a block type by name
+func (r *Repository) Delete(ctx context.Context, name string) error {
+	return r.db.Delete(ctx, constants.DbBlockTypesCollection, bson.M{"name": name})
+}
+
+// FindByName retrieves a block type by its name
+func (r *Repository) FindByName(ctx context.Context, name string) (*entities.BlockType, error) {
+	var blockType entities.BlockType
+	if err := r.db.FindOne(ctx, constants.DbBlockTypesCollection, bson.M{"name": name}, &blockType); err != nil {
+		return nil, err
+	}
+	return &blockType, nil
+}
+
+// FindAll retrieves every block type, by name
+func (r *Repository) FindAll(ctx context.Context) ([]*entities.BlockType, error) {
+	blockTypes := []*entities.BlockType{}
+	if err := r.db.FindWithPagination(ctx, constants.DbBlockTypesCollection, bson.M{}, "name", constants.SortAsc, 0, 0, &blockTypes); err != nil {
+		return nil, err
+	}
+	return blockTypes, nil
+}
+
+// CountBlocksOfType counts the content blocks, in any locale, whose content is of the given type
+func (r *Repository) CountBlocksOfType(ctx context.Context, name string) (int64, error) {
+	return r.db.Count(ctx, constants.DbContentBlocksCollection, bson.M{"type": name})
+}
+
+// FindBlocksOfType retrieves the content blocks, in any locale, whose content is of the given type
+func (r *Repository) FindBlocksOfType(ctx context.Context, name string) ([]*entities.ContentBlocks, error) {
+	contentBlocks := []*entities.ContentBlocks{}
+	if err := r.db.Find(ctx, constants.DbContentBlocksCollection, bson.M{"type": name}, &contentBlocks); err != nil {
+		return nil, err
+	}
+	return contentBlocks, nil
+}
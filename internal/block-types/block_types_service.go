@@ -0,0 +1,234 @@
+package blocktypes
+
+import (
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/entities"
+	"company-name/internal/block-types/dtos"
+	"company-name/pkg/database"
+	"company-name/pkg/errors"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/schema"
+	"company-name/pkg/validators"
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// Prefixes of the fields of validation errors: the JSON pointers of the violations of a block content, e.g.
+// "Content#/items/0/question", and the keys of the blocks a schema rejects, e.g. "Blocks#home/faq/en".
+const (
+	contentField = "Content#"
+	blocksField  = "Blocks#"
+)
+
+// IBlockTypesService manages the types of structured content blocks and validates the content of typed blocks against
+// the JSON Schema of their type.
+type IBlockTypesService interface {
+	CreateBlockType(ctx context.Context, req *dtos.CreateBlockTypeRequest) (*dtos.BlockTypeDto, error)
+	UpdateBlockType(ctx context.Context, req *dtos.UpdateBlockTypeRequest) (*dtos.BlockTypeDto, error)
+	DeleteBlockType(ctx context.Context, req *dtos.BlockTypeRequest) error
+	GetBlockType(ctx context.Context, req *dtos.BlockTypeRequest) (*dtos.BlockTypeDto, error)
+	GetBlockTypes(ctx context.Context) (*dtos.GetBlockTypesResponse, error)
+	ValidateContent(ctx context.Context, typeName, content string) error
+}
+
+type Service struct {
+	repo      IBlockTypeRepository
+	validator validators.IValidator
+}
+
+// NewBlockTypesService initializes a new block types service
+func NewBlockTypesService(repo IBlockTypeRepository, validator validators.IValidator) IBlockTypesService {
+	return &Service{
+		repo:      repo,
+		validator: validator,
+	}
+}
+
+// CreateBlockType registers a new type of block, provided its schema is valid JSON Schema.
+func (s *Service) CreateBlockType(ctx context.Context, req *dtos.CreateBlockTypeRequest) (*dtos.BlockTypeDto, error) {
+	blockType := req.ToEntity()
+	if err := s.validate(blockType); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, blockType); err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, errors.Conflict(err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgBlockTypeResource), err)
+	}
+
+	return dtos.BlockTypeDtoFromEntity(blockType), nil
+}
+
+// UpdateBlockType replaces the description and schema of a block type. The change is rejected when the new schema
+// rejects the draft or published content of blocks of the type, which are reported.
+func (s *Service) UpdateBlockType(ctx context.Context, req *dtos.UpdateBlockTypeRequest) (*dtos.BlockTypeDto, error) {
+	blockType, err := s.find(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(blockType)
+	if err := s.validate(blockType); err != nil {
+		return nil, err
+	}
+	if err := s.validateBlocksOfType(ctx, blockType); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, blockType); err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgBlockTypeResource), err)
+	}
+
+	return dtos.BlockTypeDtoFromEntity(blockType), nil
+}
+
+// DeleteBlockType removes a block type no block is of.
+func (s *Service) DeleteBlockType(ctx context.Context, req *dtos.BlockTypeRequest) error {
+	count, err := s.repo.CountBlocksOfType(ctx, req.Name)
+	if err != nil {
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+	if count > 0 {
+		return errors.ConflictM(msgkey.ErrBlockTypeInUse, nil, strconv.FormatInt(count, 10))
+	}
+
+	if err := s.repo.Delete(ctx, req.Name); err != nil {
+		if database.IsNotFound(err) {
+			return errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgBlockTypeResource), err)
+		}
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceDeleted, msgkey.MsgBlockTypeResource), err)
+	}
+
+	return nil
+}
+
+// GetBlockType returns a block type by name.
+func (s *Service) GetBlockType(ctx context.Context, req *dtos.BlockTypeRequest) (*dtos.BlockTypeDto, error) {
+	blockType, err := s.find(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return dtos.BlockTypeDtoFromEntity(blockType), nil
+}
+
+// GetBlockTypes returns every block type, by name.
+func (s *Service) GetBlockTypes(ctx context.Context) (*dtos.GetBlockTypesResponse, error) {
+	blockTypes, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgBlockTypeResource), err)
+	}
+
+	return dtos.GetBlockTypesResponseFromEntity(blockTypes), nil
+}
+
+// ValidateContent checks that the content of a block is a JSON document satisfying the schema of its type. Violations
+// are reported by JSON pointer to the offending value, prefixed with the content field.
+func (s *Service) ValidateContent(ctx context.Context, typeName, content string) error {
+	blockType, err := s.repo.FindByName(ctx, typeName)
+	if err != nil {
+		if database.IsNotFound(err) {
+			return errors.ValidationErrors(map[string]string{"Type": loc.L(msgkey.ErrUnknownBlockType, typeName)})
+		}
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgBlockTypeResource), err)
+	}
+
+	compiled, err := schema.Compile(blockType.Schema)
+	if err != nil {
+		return errors.InternalServerError(err)
+	}
+
+	violations, err := compiled.Validate(content)
+	if err != nil {
+		return errors.ValidationErrors(map[string]string{"Content": loc.L(msgkey.ErrContentNotJSON, err.Error())})
+	}
+	if len(violations) > 0 {
+		fieldErrors := make(map[string]string, len(violations))
+		for pointer, violation := range violations {
+			fieldErrors[contentField+pointer] = violation
+		}
+		return errors.ValidationErrors(fieldErrors)
+	}
+
+	return nil
+}
+
+// validate checks the fields of a block type and that its schema compiles.
+func (s *Service) validate(blockType *entities.BlockType) error {
+	if err := s.validator.ValidateStruct(blockType); err != nil {
+		return err
+	}
+
+	if _, err := schema.Compile(blockType.Schema); err != nil {
+		return errors.ValidationErrors(map[string]string{"Schema": loc.L(msgkey.ErrInvalidJSONSchema, err.Error())})
+	}
+	return nil
+}
+
+// validateBlocksOfType checks the content of the blocks of a type against its schema. Every block rejected is reported
+// by its key, e.g. "Blocks#home/faq/en", with the first violation found.
+func (s *Service) validateBlocksOfType(ctx context.Context, blockType *entities.BlockType) error {
+	contentBlocks, err := s.repo.FindBlocksOfType(ctx, blockType.Name)
+	if err != nil {
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+
+	compiled, err := schema.Compile(blockType.Schema)
+	if err != nil {
+		return errors.InternalServerError(err)
+	}
+
+	fieldErrors := map[string]string{}
+	for _, contentBlock := range contentBlocks {
+		contents := []string{contentBlock.Content}
+		if contentBlock.IsPublished() && contentBlock.PublishedFormat == constants.BlockFormatJSON {
+			contents = append(contents, contentBlock.PublishedContent)
+		}
+		for _, content := range contents {
+			if violation := firstViolation(compiled, content); violation != "" {
+				key := contentBlock.Key
+				fieldErrors[blocksField+key.Page+"/"+key.Section+"/"+key.Locale] = violation
+				break
+			}
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		fieldErrors["Schema"] = loc.L(msgkey.ErrSchemaRejectsBlocks, strconv.Itoa(len(fieldErrors)))
+		return errors.ValidationErrors(fieldErrors)
+	}
+	return nil
+}
+
+// firstViolation returns the violation of the content reported first by JSON pointer, empty when the content is valid.
+func firstViolation(compiled *schema.Schema, content string) string {
+	violations, err := compiled.Validate(content)
+	if err != nil {
+		return loc.L(msgkey.ErrContentNotJSON, err.Error())
+	}
+	if len(violations) == 0 {
+		return ""
+	}
+
+	pointer := slices.Min(slices.Collect(maps.Keys(violations)))
+	if pointer == "" {
+		return violations[pointer] // The document itself
+	}
+	return pointer + ": " + violations[pointer]
+}
+
+func (s *Service) find(ctx context.Context, name string) (*entities.BlockType, error) {
+	blockType, err := s.repo.FindByName(ctx, name)
+	if err != nil {
+		if database.IsNotFound(err) {
+			return nil, errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgBlockTypeResource), err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgBlockTypeResource), err)
+	}
+	return blockType, nil
+}
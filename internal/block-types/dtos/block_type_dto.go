@@ -0,0 +1,85 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"company-name/pkg/idgenerator"
+	"encoding/json"
+	"time"
+)
+
+type CreateBlockTypeRequest struct {
+	Name        string          `json:"name" validate:"required,max=100"`
+	Description string          `json:"description" validate:"max=500"`
+	Schema      json.RawMessage `json:"schema" validate:"required"` // JSON Schema the content of the blocks of the type must satisfy
+}
+
+func (req *CreateBlockTypeRequest) ToEntity() *entities.BlockType {
+	return &entities.BlockType{
+		ID:          idgenerator.GenerateID(),
+		Name:        req.Name,
+		Description: req.Description,
+		Schema:      string(req.Schema),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+type UpdateBlockTypeRequest struct {
+	Name        string          `json:"-" validate:"required"`
+	Description string          `json:"description" validate:"max=500"`
+	Schema      json.RawMessage `json:"schema" validate:"required"`
+}
+
+// ApplyTo copies the updated description and schema onto the stored block type.
+func (req *UpdateBlockTypeRequest) ApplyTo(blockType *entities.BlockType) {
+	blockType.Description = req.Description
+	blockType.Schema = string(req.Schema)
+	blockType.UpdatedAt = time.Now()
+}
+
+type BlockTypeRequest struct {
+	Name string `json:"-" validate:"required"`
+}
+
+type BlockTypeDto struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func BlockTypeDtoFromEntity(entity *entities.BlockType) *BlockTypeDto {
+	return &BlockTypeDto{
+		Name:        entity.Name,
+		Description: entity.Description,
+		Schema:      json.RawMessage(entity.Schema),
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}
+}
+
+// InLocation renders the timestamps of the block type in the given location.
+func (dto *BlockTypeDto) InLocation(location *time.Location) {
+	dto.CreatedAt = dto.CreatedAt.In(location)
+	dto.UpdatedAt = dto.UpdatedAt.In(location)
+}
+
+type GetBlockTypesResponse struct {
+	BlockTypes []BlockTypeDto `json:"block_types"`
+}
+
+func GetBlockTypesResponseFromEntity(blockTypes []*entities.BlockType) *GetBlockTypesResponse {
+	dtos := make([]BlockTypeDto, 0, len(blockTypes))
+	for _, blockType := range blockTypes {
+		dtos = append(dtos, *BlockTypeDtoFromEntity(blockType))
+	}
+	return &GetBlockTypesResponse{BlockTypes: dtos}
+}
+
+// InLocation renders the timestamps of the block types in the given location.
+func (dto *GetBlockTypesResponse) InLocation(location *time.Location) {
+	for i := range dto.BlockTypes {
+		dto.BlockTypes[i].InLocation(location)
+	}
+}
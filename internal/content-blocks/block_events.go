@@ -0,0 +1,90 @@
+package blocks
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/internal/content-blocks/dtos"
+	"company-name/pkg/events"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PageTopic returns the topic of the events of the blocks of a page of a tenant, nil for the platform itself.
+func PageTopic(tenantID *primitive.ObjectID, page string) string {
+	return tenantOrNil(tenantID).Hex() + "/" + page
+}
+
+// ContextPageTopic returns the topic of the events of the blocks of a page of the tenant the context is scoped to.
+func ContextPageTopic(ctx context.Context, page string) string {
+	return PageTopic(contextTenant(ctx), page)
+}
+
+// pendingEventsKey is the context key of the events of the writes made in a transaction, published once it commits.
+type pendingEventsKey struct{}
+
+// EventContentBlockRepository publishes an event on the topic of the page of every block it writes, see PageTopic.
+// Blocks carry their tenant, so that writes made across tenants, e.g. by the publish scheduler, reach the right page.
+// Writes made in a transaction are published once it commits, and never if it is rolled back. Writes made by other
+// processes publish no event here.
+type EventContentBlockRepository struct {
+	IContentBlockRepository
+	broker *events.Broker
+}
+
+// NewEventContentBlockRepository wraps repo so that its writes are published through broker.
+func NewEventContentBlockRepository(repo IContentBlockRepository, broker *events.Broker) IContentBlockRepository {
+	return &EventContentBlockRepository{IContentBlockRepository: repo, broker: broker}
+}
+
+func (r *EventContentBlockRepository) CreateContentBlock(ctx context.Context, contentBlock *entities.ContentBlocks) (*entities.ContentBlocks, error) {
+	created, err := r.IContentBlockRepository.CreateContentBlock(ctx, contentBlock)
+	if err == nil {
+		r.publish(ctx, constants.BlockEventCreated, created)
+	}
+	return created, err
+}
+
+func (r *EventContentBlockRepository) UpdateContentBlock(ctx context.Context, contentBlock *entities.ContentBlocks) (*entities.ContentBlocks, error) {
+	updated, err := r.IContentBlockRepository.UpdateContentBlock(ctx, contentBlock)
+	if err == nil {
+		r.publish(ctx, constants.BlockEventUpdated, updated)
+	}
+	return updated, err
+}
+
+func (r *EventContentBlockRepository) DeleteContentBlock(ctx context.Context, contentBlock *entities.ContentBlocks) error {
+	err := r.IContentBlockRepository.DeleteContentBlock(ctx, contentBlock)
+	if err == nil {
+		r.publish(ctx, constants.BlockEventDeleted, contentBlock)
+	}
+	return err
+}
+
+// WithTransaction holds back the events of the writes made in the transaction until it commits. Those of an attempt
+// that is retried are dropped along with its writes.
+func (r *EventContentBlockRepository) WithTransaction(ctx context.Context, function func(ctx context.Context) error) error {
+	var pending []func()
+	err := r.IContentBlockRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		pending = nil
+		return function(context.WithValue(ctx, pendingEventsKey{}, &pending))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, publish := range pending {
+		publish()
+	}
+	return nil
+}
+
+// publish sends the event of a write, or holds it back when the write is part of a transaction.
+func (r *EventContentBlockRepository) publish(ctx context.Context, name string, contentBlock *entities.ContentBlocks) {
+	topic, data := PageTopic(contentBlock.TenantID, contentBlock.Key.Page), dtos.BlockEventDtoFromEntity(contentBlock)
+	if pending, ok := ctx.Value(pendingEventsKey{}).(*[]func()); ok {
+		*pending = append(*pending, func() { r.broker.Publish(topic, name, data) })
+		return
+	}
+	r.broker.Publish(topic, name, data)
+}
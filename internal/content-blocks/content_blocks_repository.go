@@ -5,20 +5,46 @@ import (
 	"context"
 	"errors"
 	"go.mongodb.org/mongo-driver/mongo"
+	"slices"
+	"strings"
 	"time"
 
 	"company-name/entities"
 	"company-name/pkg/database"
+	"company-name/pkg/localization"
+	"company-name/pkg/render"
+	"company-name/pkg/utils/filerefs"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// SearchHit is a variant matching a full-text search, along with the relevance computed by the text index.
+type SearchHit struct {
+	entities.ContentBlocks `bson:",inline"`
+	Score                  float64 `bson:"score"`
+}
+
 type IContentBlockRepository interface {
 	CreateContentBlock(ctx context.Context, contentBlock *entities.ContentBlocks) (*entities.ContentBlocks, error)
 	UpdateContentBlock(ctx context.Context, contentBlock *entities.ContentBlocks) (*entities.ContentBlocks, error)
-	DeleteContentBlock(ctx context.Context, key entities.BlockKey) error
+	DeleteContentBlock(ctx context.Context, contentBlock *entities.ContentBlocks) error
 	GetPageContentBlocks(ctx context.Context, page string) ([]*entities.ContentBlocks, error)
+	GetSectionVariants(ctx context.Context, page, section string) ([]*entities.ContentBlocks, error)
+	GetAllContentBlocks(ctx context.Context) ([]*entities.ContentBlocks, error)
 	GetContentBlock(ctx context.Context, key entities.BlockKey) (*entities.ContentBlocks, error)
+	FindIncluding(ctx context.Context, page, section string) ([]*entities.ContentBlocks, error)
+	FindPublishedPages(ctx context.Context) (map[string]time.Time, error)
+	FindUsingFile(ctx context.Context, fileID primitive.ObjectID) ([]*entities.ContentBlocks, error)
+	FindReferencedFiles(ctx context.Context) (map[primitive.ObjectID]bool, error)
+	Reindex(ctx context.Context, contentBlock *entities.ContentBlocks) (bool, error)
+	Search(ctx context.Context, query, locale string, page, pageSize int) ([]*SearchHit, int64, error)
+	FindDueForScheduling(ctx context.Context, now time.Time) ([]*entities.ContentBlocks, error)
+	CreateRevision(ctx context.Context, revision *entities.ContentBlockRevision) error
+	DeleteRevision(ctx context.Context, revision *entities.ContentBlockRevision) error
+	CountRevisions(ctx context.Context, blockID primitive.ObjectID) (int64, error)
+	FindRevisions(ctx context.Context, blockID primitive.ObjectID) ([]*entities.ContentBlockRevision, error)
+	FindRevision(ctx context.Context, blockID primitive.ObjectID, revision int64) (*entities.ContentBlockRevision, error)
+	WithTransaction(ctx context.Context, function func(ctx context.Context) error) error
 }
 
 type ContentBlockRepository struct {
@@ -35,7 +61,11 @@ func (r *ContentBlockRepository) CreateContentBlock(ctx context.Context, content
 	contentBlock.ID = primitive.NewObjectID()
 	contentBlock.CreatedAt = time.Now()
 	contentBlock.UpdatedAt = time.Now()
+	contentBlock.Version = 1
 
+	if err := r.derive(ctx, contentBlock); err != nil {
+		return nil, err
+	}
 	if err := r.db.Create(ctx, constants.DbContentBlocksCollection, contentBlock); err != nil {
 		return nil, err
 	}
@@ -43,34 +73,53 @@ func (r *ContentBlockRepository) CreateContentBlock(ctx context.Context, content
 	return contentBlock, nil
 }
 
+// UpdateContentBlock saves the block provided it is still at the version it was read at, and increments its version.
 func (r *ContentBlockRepository) UpdateContentBlock(ctx context.Context, contentBlock *entities.ContentBlocks) (*entities.ContentBlocks, error) {
+	if err := r.derive(ctx, contentBlock); err != nil {
+		return nil, err
+	}
+
+	expectedVersion := contentBlock.Version
+	contentBlock.Version = expectedVersion + 1
 	contentBlock.UpdatedAt = time.Now()
 
-	filter := bson.M{"key.page": contentBlock.Key.Page, "key.section": contentBlock.Key.Section}
+	filter := bson.M{
+		"_id":     contentBlock.ID,
+		"version": database.VersionFilter(expectedVersion),
+	}
 	update := bson.M{
 		"$set": contentBlock,
 	}
 
 	if err := r.db.Update(ctx, constants.DbContentBlocksCollection, filter, update); err != nil {
+		contentBlock.Version = expectedVersion
 		return nil, err
 	}
 
 	return contentBlock, nil
 }
 
+// GetPageContentBlocks retrieves every variant of every block of a page
 func (r *ContentBlockRepository) GetPageContentBlocks(ctx context.Context, page string) ([]*entities.ContentBlocks, error) {
-	filter := bson.M{"key.page": page}
-	var contentBlocks []*entities.ContentBlocks
+	return r.find(ctx, bson.M{"key.page": page})
+}
 
-	if err := r.db.Find(ctx, constants.DbContentBlocksCollection, filter, &contentBlocks); err != nil {
-		return nil, err
-	}
+// GetSectionVariants retrieves the variants of a block in every locale
+func (r *ContentBlockRepository) GetSectionVariants(ctx context.Context, page, section string) ([]*entities.ContentBlocks, error) {
+	return r.find(ctx, bson.M{"key.page": page, "key.section": section})
+}
 
-	return contentBlocks, nil
+func (r *ContentBlockRepository) GetAllContentBlocks(ctx context.Context) ([]*entities.ContentBlocks, error) {
+	return r.find(ctx, bson.M{})
 }
 
+// GetContentBlock retrieves the variant of a block in the locale of the key. Blocks written before variants existed
+// have no locale and are found as the variant in the default language.
 func (r *ContentBlockRepository) GetContentBlock(ctx context.Context, key entities.BlockKey) (*entities.ContentBlocks, error) {
-	filter := bson.M{"key.page": key.Page, "key.section": key.Section}
+	filter := bson.M{"key.page": key.Page, "key.section": key.Section, "key.locale": key.Locale}
+	if key.Locale == localization.DefaultLang {
+		filter["key.locale"] = bson.M{"$in": bson.A{key.Locale, nil}}
+	}
 	var contentBlock entities.ContentBlocks
 
 	if err := r.db.FindOne(ctx, constants.DbContentBlocksCollection, filter, &contentBlock); err != nil {
@@ -80,11 +129,180 @@ func (r *ContentBlockRepository) GetContentBlock(ctx context.Context, key entiti
 		return nil, err
 	}
 
+	contentBlock.FillLegacyFields()
 	return &contentBlock, nil
 }
 
-func (r *ContentBlockRepository) DeleteContentBlock(ctx context.Context, key entities.BlockKey) error {
-	filter := bson.M{"key.page": key.Page, "key.section": key.Section}
+// FindIncluding retrieves the variants whose draft or published content includes the given block.
+func (r *ContentBlockRepository) FindIncluding(ctx context.Context, page, section string) ([]*entities.ContentBlocks, error) {
+	pattern := render.IncludePattern(page, section)
+	filter := bson.M{"$or": bson.A{
+		bson.M{"content": bson.M{"$regex": pattern}},
+		bson.M{"published_content": bson.M{"$regex": pattern}},
+	}}
+	return r.find(ctx, filter)
+}
+
+// FindPublishedPages retrieves the pages having at least one variant visible to the public, those written before
+// drafts existed included, with the time the most recent of them was updated.
+func (r *ContentBlockRepository) FindPublishedPages(ctx context.Context) (map[string]time.Time, error) {
+	var pages []struct {
+		Page      string    `bson:"_id"`
+		UpdatedAt time.Time `bson:"updated_at"`
+	}
+
+	filter := bson.M{"status": bson.M{"$in": bson.A{constants.BlockStatusPublished, nil}}}
+	stages := []bson.M{{"$group": bson.M{"_id": "$key.page", "updated_at": bson.M{"$max": "$updated_at"}}}}
+	if err := r.db.Aggregate(ctx, constants.DbContentBlocksCollection, filter, stages, &pages); err != nil {
+		return nil, err
+	}
+
+	modified := make(map[string]time.Time, len(pages))
+	for _, page := range pages {
+		modified[page.Page] = page.UpdatedAt
+	}
+	return modified, nil
+}
+
+// FindUsingFile retrieves the variants whose draft or published content refers to a stored file.
+func (r *ContentBlockRepository) FindUsingFile(ctx context.Context, fileID primitive.ObjectID) ([]*entities.ContentBlocks, error) {
+	return r.find(ctx, bson.M{"file_ids": fileID})
+}
+
+// FindReferencedFiles retrieves the stored files that the content of at least one block refers to.
+func (r *ContentBlockRepository) FindReferencedFiles(ctx context.Context) (map[primitive.ObjectID]bool, error) {
+	contentBlocks, err := r.find(ctx, bson.M{"file_ids.0": bson.M{"$exists": true}})
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := map[primitive.ObjectID]bool{}
+	for _, contentBlock := range contentBlocks {
+		for _, fileID := range contentBlock.FileIDs {
+			referenced[fileID] = true
+		}
+	}
+	return referenced, nil
+}
+
+// Reindex recomputes the data a block derives from its content, e.g. for blocks written before it was kept, and saves
+// it when it changed, reporting whether it did. The block is not otherwise written, so its version is left unchanged.
+func (r *ContentBlockRepository) Reindex(ctx context.Context, contentBlock *entities.ContentBlocks) (bool, error) {
+	storedFiles, storedText := contentBlock.FileIDs, contentBlock.SearchText
+	if err := r.derive(ctx, contentBlock); err != nil {
+		return false, err
+	}
+	if slices.Equal(storedFiles, contentBlock.FileIDs) && storedText == contentBlock.SearchText {
+		return false, nil
+	}
+
+	update := bson.M{"$set": bson.M{"file_ids": contentBlock.FileIDs, "search_text": contentBlock.SearchText}}
+	if err := r.db.Update(ctx, constants.DbContentBlocksCollection, bson.M{"_id": contentBlock.ID}, update); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Search retrieves a page of the variants matching a full-text query over their content, page and section, most
+// relevant first, optionally in a single locale. Blocks are only found once their search text was derived.
+func (r *ContentBlockRepository) Search(ctx context.Context, query, locale string, page, pageSize int) ([]*SearchHit, int64, error) {
+	filter := bson.M{"$text": bson.M{"$search": query}}
+	if locale != "" {
+		filter["key.locale"] = locale
+		if locale == localization.DefaultLang {
+			filter["key.locale"] = bson.M{"$in": bson.A{locale, nil}}
+		}
+	}
+
+	totalCount, err := r.db.Count(ctx, constants.DbContentBlocksCollection, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+
+	var hits []*SearchHit
+	if err := r.db.FindByTextScore(ctx, constants.DbContentBlocksCollection, filter, int64(offset), int64(pageSize), &hits); err != nil {
+		return nil, 0, err
+	}
+
+	for _, hit := range hits {
+		hit.FillLegacyFields()
+	}
+	return hits, totalCount, nil
+}
+
+// derive sets the data a block derives from its draft and published content.
+func (r *ContentBlockRepository) derive(ctx context.Context, contentBlock *entities.ContentBlocks) error {
+	contentBlock.SearchText = searchText(contentBlock)
+	return r.trackFiles(ctx, contentBlock)
+}
+
+// trackFiles sets the stored files of the tenant of a block that its draft or published content refers to, by id or
+// by path, see filerefs.Candidates.
+func (r *ContentBlockRepository) trackFiles(ctx context.Context, contentBlock *entities.ContentBlocks) error {
+	contentBlock.FileIDs = nil
+
+	paths, ids := filerefs.Candidates(contentBlock.Content, contentBlock.PublishedContent)
+	if len(paths) == 0 && len(ids) == 0 {
+		return nil
+	}
+
+	// Blocks written across tenants, e.g. by the publish scheduler, carry their tenant; new blocks get the one of the
+	// context once created
+	tenantID := contentBlock.TenantID
+	if tenantID == nil {
+		tenantID = contextTenant(ctx)
+	}
+	filter := bson.M{
+		database.TenantField: tenantID,
+		"$or": bson.A{
+			bson.M{"_id": bson.M{"$in": ids}},
+			bson.M{"path": bson.M{"$in": paths}},
+		},
+	}
+	var files []*entities.File
+	if err := r.db.Find(ctx, constants.DbFilesCollection, filter, &files); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		contentBlock.FileIDs = append(contentBlock.FileIDs, file.ID)
+	}
+	slices.SortFunc(contentBlock.FileIDs, func(a, b primitive.ObjectID) int {
+		return strings.Compare(a.Hex(), b.Hex())
+	})
+	return nil
+}
+
+// FindDueForScheduling retrieves the blocks whose scheduled publishing or unpublishing time has come.
+func (r *ContentBlockRepository) FindDueForScheduling(ctx context.Context, now time.Time) ([]*entities.ContentBlocks, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"publish_at": bson.M{"$lte": now}},
+		bson.M{"unpublish_at": bson.M{"$lte": now}},
+	}}
+	return r.find(ctx, filter)
+}
+
+func (r *ContentBlockRepository) find(ctx context.Context, filter bson.M) ([]*entities.ContentBlocks, error) {
+	var contentBlocks []*entities.ContentBlocks
+
+	if err := r.db.Find(ctx, constants.DbContentBlocksCollection, filter, &contentBlocks); err != nil {
+		return nil, err
+	}
+
+	for _, contentBlock := range contentBlocks {
+		contentBlock.FillLegacyFields()
+	}
+	return contentBlocks, nil
+}
+
+// DeleteContentBlock removes the block provided it is still at the version it was read at.
+func (r *ContentBlockRepository) DeleteContentBlock(ctx context.Context, contentBlock *entities.ContentBlocks) error {
+	filter := bson.M{
+		"_id":     contentBlock.ID,
+		"version": database.VersionFilter(contentBlock.Version),
+	}
 
 	if err := r.db.Delete(ctx, constants.DbContentBlocksCollection, filter); err != nil {
 		return err
@@ -92,3 +310,47 @@ func (r *ContentBlockRepository) DeleteContentBlock(ctx context.Context, key ent
 
 	return nil
 }
+
+// CreateRevision records a revision of a block. Revisions are unique per block and number, so that two concurrent
+// writes of the same version cannot both be recorded.
+func (r *ContentBlockRepository) CreateRevision(ctx context.Context, revision *entities.ContentBlockRevision) error {
+	return r.db.Create(ctx, constants.DbBlockRevisionsCollection, revision)
+}
+
+// DeleteRevision removes a revision whose write of the block failed. Revisions are otherwise never removed.
+func (r *ContentBlockRepository) DeleteRevision(ctx context.Context, revision *entities.ContentBlockRevision) error {
+	return r.db.Delete(ctx, constants.DbBlockRevisionsCollection, bson.M{"_id": revision.ID})
+}
+
+func (r *ContentBlockRepository) CountRevisions(ctx context.Context, blockID primitive.ObjectID) (int64, error) {
+	return r.db.Count(ctx, constants.DbBlockRevisionsCollection, bson.M{"block_id": blockID})
+}
+
+// FindRevisions retrieves the revisions of a block, most recent first
+func (r *ContentBlockRepository) FindRevisions(ctx context.Context, blockID primitive.ObjectID) ([]*entities.ContentBlockRevision, error) {
+	revisions := []*entities.ContentBlockRevision{}
+	filter := bson.M{"block_id": blockID}
+
+	if err := r.db.FindWithPagination(ctx, constants.DbBlockRevisionsCollection, filter, "revision", constants.SortDesc, 0, 0, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *ContentBlockRepository) FindRevision(ctx context.Context, blockID primitive.ObjectID, revision int64) (*entities.ContentBlockRevision, error) {
+	filter := bson.M{"block_id": blockID, "revision": revision}
+	var result entities.ContentBlockRevision
+
+	if err := r.db.FindOne(ctx, constants.DbBlockRevisionsCollection, filter, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// WithTransaction runs function in a transaction. The operations made with the context it is given, through this
+// repository or any other sharing the database, are committed together or not at all.
+func (r *ContentBlockRepository) WithTransaction(ctx context.Context, function func(ctx context.Context) error) error {
+	return r.db.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		return function(sessCtx)
+	})
+}
@@ -1,13 +1,32 @@
 package blocks
 
 import (
+	"bytes"
+	"company-name/constants"
 	"company-name/constants/msgkey"
 	"company-name/entities"
+	"company-name/internal/block-types"
 	"company-name/internal/content-blocks/dtos"
+	"company-name/internal/page"
+	"company-name/internal/variable"
+	"company-name/pkg/database"
 	"company-name/pkg/errors"
+	"company-name/pkg/idgenerator"
 	loc "company-name/pkg/localization"
+	"company-name/pkg/render"
+	"company-name/pkg/sanitizer"
+	"company-name/pkg/utils/diff"
+	"company-name/pkg/utils/etag"
+	"company-name/pkg/utils/textsearch"
 	"company-name/pkg/validators"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
 )
 
 // IContentBlocksService defines methods for managing content blocks, including creation, update, deletion, and retrieval.
@@ -16,26 +35,68 @@ import (
 // DeleteBlock removes a content block identified by the given request DTO and returns an error if the operation fails.
 // GetBlock retrieves a single content block based on the page and section specified in the request DTO and returns it or an error.
 // GetPage fetches all content blocks for a given page specified in the request DTO and returns them or an error.
+// GetRevisions lists the revisions of a block, DiffRevisions compares two of them and RollbackRevision restores one.
+// PublishBlock and UnpublishBlock change, now or at a scheduled time, whether the public sees a block.
+// GetMissingTranslations lists the blocks without a variant in a locale.
+// SanitizeBlocks re-applies the sanitization policy to the stored blocks.
+// ReindexBlocks recomputes the data derived from the content of the stored blocks.
+// ExportBundle and ImportBundle carry pages and their blocks from an environment to another.
+// GetDependents lists the blocks that include a block.
+// SearchBlocks finds the blocks whose content, page or section match a full-text query.
 type IContentBlocksService interface {
 	CreateBlock(ctx context.Context, dto *dtos.CreateContentBlockRequest) (*dtos.CreateContentBlockResponse, error)
 	UpdateBlock(ctx context.Context, dto *dtos.UpdateContentBlockRequest) (*dtos.UpdateContentBlockResponse, error)
 	DeleteBlock(ctx context.Context, dto *dtos.DeleteBlockRequest) error
 	GetBlock(ctx context.Context, dto *dtos.GetContentBlockRequest) (*dtos.GetContentBlockResponse, error)
 	GetPage(ctx context.Context, dto *dtos.GetPageContentBlocksRequest) (*dtos.GetPageContentBlocksResponse, error)
+	GetRevisions(ctx context.Context, dto *dtos.GetRevisionsRequest) (*dtos.GetRevisionsResponse, error)
+	DiffRevisions(ctx context.Context, dto *dtos.DiffRevisionsRequest) (*dtos.RevisionDiffDto, error)
+	RollbackRevision(ctx context.Context, dto *dtos.RollbackRevisionRequest) (*dtos.UpdateContentBlockResponse, error)
+	PublishBlock(ctx context.Context, dto *dtos.PublishBlockRequest) (*dtos.UpdateContentBlockResponse, error)
+	UnpublishBlock(ctx context.Context, dto *dtos.UnpublishBlockRequest) (*dtos.UpdateContentBlockResponse, error)
+	GetMissingTranslations(ctx context.Context, dto *dtos.MissingTranslationsRequest) (*dtos.MissingTranslationsResponse, error)
+	SanitizeBlocks(ctx context.Context, dto *dtos.SanitizeBlocksRequest) (*dtos.SanitizeBlocksResponse, error)
+	ReindexBlocks(ctx context.Context) (*dtos.ReindexBlocksResponse, error)
+	ExportBundle(ctx context.Context, dto *dtos.ExportBundleRequest) (*dtos.ContentBundle, error)
+	ImportBundle(ctx context.Context, dto *dtos.ImportBundleRequest) (*dtos.ImportBundleResponse, error)
+	GetDependents(ctx context.Context, dto *dtos.BlockDependentsRequest) (*dtos.BlockDependentsResponse, error)
+	SearchBlocks(ctx context.Context, dto *dtos.SearchBlocksRequest) (*dtos.SearchBlocksResponse, error)
 }
 
 // ContentBlocksService provides methods for managing content blocks, including creation, update, deletion, and retrieval.
-// It utilizes IContentBlockRepository for repository operations and IValidator for input validation.
+// It utilizes IContentBlockRepository for repository operations, IValidator for input validation, ISanitizer to keep
+// disallowed markup out of HTML content and IBlockTypesService to validate the content of typed blocks. Pages are read
+// through IPageRepository for their metadata and the order of their sections, and their responses are kept in PageCache.
+// The variables blocks refer to are read through IVariableRepository.
 type ContentBlocksService struct {
-	repo      IContentBlockRepository
-	validator validators.IValidator
+	repo       IContentBlockRepository
+	pages      page.IPageRepository
+	variables  variable.IVariableRepository
+	validator  validators.IValidator
+	sanitizer  sanitizer.ISanitizer
+	blockTypes blocktypes.IBlockTypesService
+	pageCache  *PageCache
 }
 
-// NewContentBlocksService initializes and returns a new IContentBlocksService instance with the provided repository and validator.
-func NewContentBlocksService(repo IContentBlockRepository, validator validators.IValidator) IContentBlocksService {
+// NewContentBlocksService initializes and returns a new IContentBlocksService instance with the provided repositories, validator, sanitizer, block types and page cache.
+// The page cache may be nil; otherwise the repositories are expected to invalidate it, see NewCachedContentBlockRepository.
+func NewContentBlocksService(
+	repo IContentBlockRepository,
+	pages page.IPageRepository,
+	variables variable.IVariableRepository,
+	validator validators.IValidator,
+	sanitizer sanitizer.ISanitizer,
+	blockTypes blocktypes.IBlockTypesService,
+	pageCache *PageCache,
+) IContentBlocksService {
 	return &ContentBlocksService{
-		repo:      repo,
-		validator: validator,
+		repo:       repo,
+		pages:      pages,
+		variables:  variables,
+		validator:  validator,
+		sanitizer:  sanitizer,
+		blockTypes: blockTypes,
+		pageCache:  pageCache,
 	}
 }
 
@@ -46,25 +107,61 @@ func (s *ContentBlocksService) CreateBlock(ctx context.Context, dto *dtos.Create
 	if err := contentBlock.Validate(s.validator); err != nil {
 		return nil, err
 	}
+	if err := s.checkContent(ctx, contentBlock); err != nil {
+		return nil, err
+	}
+
+	// Blocks written before variants existed have no locale, which the unique index does not tell from the default one
+	existing, err := s.repo.GetContentBlock(ctx, contentBlock.Key)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+	if existing != nil {
+		return nil, errors.Conflict(nil)
+	}
 
 	createdBlock, err := s.repo.CreateContentBlock(ctx, contentBlock)
 	if err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, errors.Conflict(err)
+		}
 		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgContentBlockResource), err)
 	}
 
+	if err := s.repo.CreateRevision(ctx, newRevision(createdBlock, dto.AuthorID, nil)); err != nil {
+		// Roll back the block so that it is never left without history
+		if deleteErr := s.repo.DeleteContentBlock(ctx, createdBlock); deleteErr != nil {
+			log.Printf("Error deleting content block %s after failing to record its revision: %v", createdBlock.ID.Hex(), deleteErr)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgBlockRevisionResource), err)
+	}
+
 	return dtos.CreateContentBlockResponseFromEntity(createdBlock), nil
 }
 
-// UpdateBlock updates an existing content block based on the provided request DTO. Validates input and returns an updated response.
+// UpdateBlock updates an existing content block based on the provided request DTO, provided it is still at the version
+// the client expects. Validates input and returns an updated response.
 func (s *ContentBlocksService) UpdateBlock(ctx context.Context, dto *dtos.UpdateContentBlockRequest) (*dtos.UpdateContentBlockResponse, error) {
-	contentBlock := dto.ToEntity()
+	contentBlock, err := s.findAtVersion(ctx, entities.NewBlockKey(dto.Page, dto.Section, dto.Locale), dto.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureBaseline(ctx, contentBlock); err != nil {
+		return nil, err
+	}
+
+	dto.ApplyTo(contentBlock)
 	if err := contentBlock.Validate(s.validator); err != nil {
 		return nil, err
 	}
+	if err := s.checkContent(ctx, contentBlock); err != nil {
+		return nil, err
+	}
 
-	updatedBlock, err := s.repo.UpdateContentBlock(ctx, contentBlock)
+	updatedBlock, err := s.saveRevised(ctx, contentBlock, dto.AuthorID, nil)
 	if err != nil {
-		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgContentBlockResource), err)
+		return nil, err
 	}
 
 	return dtos.UpdateContentBlockResponseFromEntity(updatedBlock), nil
@@ -72,16 +169,17 @@ func (s *ContentBlocksService) UpdateBlock(ctx context.Context, dto *dtos.Update
 
 // DeleteBlock deletes a content block identified by the page and section in the provided request DTO. Returns an error if validation or deletion fails.
 func (s *ContentBlocksService) DeleteBlock(ctx context.Context, dto *dtos.DeleteBlockRequest) error {
-	blockKey := entities.BlockKey{
-		Page:    dto.Page,
-		Section: dto.Section,
-	}
+	blockKey := entities.NewBlockKey(dto.Page, dto.Section, dto.Locale)
 
-	if _, err := s.repo.GetContentBlock(ctx, blockKey); err == nil {
-		return errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgContentBlockResource), err)
+	contentBlock, err := s.findAtVersion(ctx, blockKey, dto.ExpectedVersion)
+	if err != nil {
+		return err
 	}
 
-	if err := s.repo.DeleteContentBlock(ctx, blockKey); err != nil {
+	if err := s.repo.DeleteContentBlock(ctx, contentBlock); err != nil {
+		if database.IsNotFound(err) {
+			return s.concurrentWriteError(ctx, blockKey, err)
+		}
 		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceDeleted, msgkey.MsgContentBlockResource), err)
 	}
 
@@ -89,26 +187,526 @@ func (s *ContentBlocksService) DeleteBlock(ctx context.Context, dto *dtos.Delete
 }
 
 // GetPage retrieves all content blocks associated with a specified page from the repository. Returns an error if retrieval fails.
+// Each block is served in the first of the requested locales it has a variant in, and is left out when it has none.
+// Unless drafts are requested, only the published variants are considered, with their published content. Sections
+// follow the order of the page metadata, those it does not list coming last in the order they are found. Includes and
+// variables are expanded before the blocks are rendered, except in raw mode where editors read them as written.
 func (s *ContentBlocksService) GetPage(ctx context.Context, dto *dtos.GetPageContentBlocksRequest) (*dtos.GetPageContentBlocksResponse, error) {
-	contentBlocks, err := s.repo.GetPageContentBlocks(ctx, dto.Page)
+	if cached, ok := s.pageCache.Get(ctx, dto); ok {
+		return cached.Clone(), nil
+	}
+
+	pageMetadata, err := s.pages.FindBySlug(ctx, dto.Page)
+	if err != nil && !database.IsNotFound(err) {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgPageResource), err)
+	}
+
+	variants, err := s.repo.GetPageContentBlocks(ctx, dto.Page)
 	if err != nil {
 		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
 	}
 
-	return dtos.GetPageContentBlocksResponseFromEntity(contentBlocks), nil
+	var sections []string
+	sectionVariants := map[string][]*entities.ContentBlocks{}
+	for _, variant := range variants {
+		if _, ok := sectionVariants[variant.Key.Section]; !ok {
+			sections = append(sections, variant.Key.Section)
+		}
+		sectionVariants[variant.Key.Section] = append(sectionVariants[variant.Key.Section], variant)
+	}
+
+	if pageMetadata != nil {
+		sections = orderSections(pageMetadata.Sections, sections)
+	}
+
+	expander := s.newExpander(dto.Locales, dto.Draft)
+	contentBlocks := make([]*entities.ContentBlocks, 0, len(sections))
+	for _, section := range sections {
+		contentBlock := variantFor(sectionVariants[section], dto.Locales, dto.Draft)
+		if contentBlock == nil {
+			continue
+		}
+		if dto.Render != constants.RenderRaw {
+			if err := expander.expand(ctx, contentBlock); err != nil {
+				return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+			}
+		}
+		if err := renderBlock(contentBlock, dto.Render); err != nil {
+			return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+		}
+		contentBlocks = append(contentBlocks, contentBlock)
+	}
+
+	response := dtos.GetPageContentBlocksResponseFromEntity(pageMetadata, contentBlocks)
+	response.ETag, response.LastModified = pageValidators(dto, pageMetadata, contentBlocks, expander)
+	s.pageCache.Add(ctx, dto, response, expander.includedPages(), expander.variableNames())
+
+	return response.Clone(), nil
 }
 
-// GetBlock retrieves a content block from the repository using the specified page and section identifiers.
+// pageValidators returns the entity tag and the last modification time of a page response. The tag is weak since the
+// same content is rendered differently depending on the language and timezone of the request. The blocks included and
+// the variables referred to count as well.
+func pageValidators(dto *dtos.GetPageContentBlocksRequest, pageMetadata *entities.Page, contentBlocks []*entities.ContentBlocks, expander *expander) (string, time.Time) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%t|%s|%s\n", dto.Draft, dto.Render, strings.Join(dto.Locales, ","))
+
+	var lastModified time.Time
+	if pageMetadata != nil {
+		fmt.Fprintf(hash, "page|%d\n", pageMetadata.UpdatedAt.UnixNano())
+		lastModified = pageMetadata.UpdatedAt
+	}
+	for _, contentBlock := range contentBlocks {
+		fmt.Fprintf(hash, "%s|%d|%d\n", contentBlock.ID.Hex(), contentBlock.Version, contentBlock.UpdatedAt.UnixNano())
+		if contentBlock.UpdatedAt.After(lastModified) {
+			lastModified = contentBlock.UpdatedAt
+		}
+	}
+	if expanded := expander.writeValidators(hash); expanded.After(lastModified) {
+		lastModified = expanded
+	}
+
+	return etag.FormatWeak(hex.EncodeToString(hash.Sum(nil)[:16])), lastModified
+}
+
+// orderSections sorts the sections found in the order of the page, followed by the ones the page does not list. The
+// sections the page lists but that have no block are left out.
+func orderSections(order, found []string) []string {
+	remaining := make(map[string]bool, len(found))
+	for _, section := range found {
+		remaining[section] = true
+	}
+
+	ordered := make([]string, 0, len(found))
+	for _, section := range order {
+		if remaining[section] {
+			ordered = append(ordered, section)
+			delete(remaining, section)
+		}
+	}
+	for _, section := range found {
+		if remaining[section] {
+			ordered = append(ordered, section)
+		}
+	}
+	return ordered
+}
+
+// GetBlock retrieves a content block from the repository using the specified page and section identifiers, in the
+// first of the requested locales it has a variant in, with its includes and variables expanded unless it is requested
+// raw.
 func (s *ContentBlocksService) GetBlock(ctx context.Context, dto *dtos.GetContentBlockRequest) (*dtos.GetContentBlockResponse, error) {
-	key := entities.BlockKey{
-		Page:    dto.Page,
-		Section: dto.Section,
+	variants, err := s.repo.GetSectionVariants(ctx, dto.Page, dto.Section)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+
+	contentBlock := variantFor(variants, dto.Locales, dto.Draft)
+	if contentBlock == nil {
+		return nil, errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgContentBlockResource), nil)
 	}
 
-	contentBlocks, err := s.repo.GetContentBlock(ctx, key)
+	if dto.Render != constants.RenderRaw {
+		if err := s.newExpander(dto.Locales, dto.Draft).expand(ctx, contentBlock); err != nil {
+			return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+		}
+	}
+	if err := renderBlock(contentBlock, dto.Render); err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+
+	return dtos.GetContentBlockResponseFromEntity(contentBlock), nil
+}
+
+// GetMissingTranslations lists the blocks, of a page or of every page, that have no variant in the requested locale.
+func (s *ContentBlocksService) GetMissingTranslations(ctx context.Context, dto *dtos.MissingTranslationsRequest) (*dtos.MissingTranslationsResponse, error) {
+	var variants []*entities.ContentBlocks
+	var err error
+	if dto.Page != "" {
+		variants, err = s.repo.GetPageContentBlocks(ctx, dto.Page)
+	} else {
+		variants, err = s.repo.GetAllContentBlocks(ctx)
+	}
 	if err != nil {
 		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
 	}
 
-	return dtos.GetContentBlockResponseFromEntity(contentBlocks), nil
+	return dtos.MissingTranslationsResponseFromEntity(strings.ToLower(dto.Locale), variants), nil
+}
+
+// renderBlock replaces the content of a block read by its rendering, HTML unless another one is requested. The format
+// of the block is kept so that clients know what it was written in.
+func renderBlock(contentBlock *entities.ContentBlocks, mode string) error {
+	if mode == "" {
+		mode = constants.RenderHTML
+	}
+
+	rendered, err := render.Render(contentBlock.Content, contentBlock.Format, mode)
+	if err != nil {
+		return err
+	}
+	contentBlock.Content = rendered
+	return nil
+}
+
+// variantFor chooses the variant of a block to serve: the one in the first of the locales that has one visible to the
+// reader. Public readers only see published variants, with their published content.
+func variantFor(variants []*entities.ContentBlocks, locales []string, draft bool) *entities.ContentBlocks {
+	for _, locale := range locales {
+		for _, variant := range variants {
+			if variant.Key.Locale != locale {
+				continue
+			}
+			if draft {
+				return variant
+			}
+			if variant.IsPublished() {
+				return variant.PublishedView()
+			}
+		}
+	}
+	return nil
+}
+
+// PublishBlock makes the current draft of a content block visible to the public, or schedules it to be when a future
+// time is given. Publishing again replaces the published content with the current draft.
+func (s *ContentBlocksService) PublishBlock(ctx context.Context, dto *dtos.PublishBlockRequest) (*dtos.UpdateContentBlockResponse, error) {
+	contentBlock, err := s.findAtVersion(ctx, entities.NewBlockKey(dto.Page, dto.Section, dto.Locale), dto.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if dto.PublishAt != nil && dto.PublishAt.After(now) {
+		contentBlock.PublishAt = dto.PublishAt
+	} else {
+		contentBlock.Publish(now)
+	}
+
+	return s.saveSchedule(ctx, contentBlock)
+}
+
+// UnpublishBlock hides a content block from the public, or schedules it to be when a future time is given. Hiding it
+// immediately also cancels its scheduled publishing.
+func (s *ContentBlocksService) UnpublishBlock(ctx context.Context, dto *dtos.UnpublishBlockRequest) (*dtos.UpdateContentBlockResponse, error) {
+	contentBlock, err := s.findAtVersion(ctx, entities.NewBlockKey(dto.Page, dto.Section, dto.Locale), dto.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if dto.UnpublishAt != nil && dto.UnpublishAt.After(time.Now()) {
+		contentBlock.UnpublishAt = dto.UnpublishAt
+	} else {
+		contentBlock.Unpublish()
+		contentBlock.PublishAt = nil
+	}
+
+	return s.saveSchedule(ctx, contentBlock)
+}
+
+// saveSchedule saves a change of the publishing state of a block. Its content is unchanged, so no revision is recorded.
+func (s *ContentBlocksService) saveSchedule(ctx context.Context, contentBlock *entities.ContentBlocks) (*dtos.UpdateContentBlockResponse, error) {
+	updatedBlock, err := s.repo.UpdateContentBlock(ctx, contentBlock)
+	if err != nil {
+		if database.IsNotFound(err) {
+			return nil, s.concurrentWriteError(ctx, contentBlock.Key, err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgContentBlockResource), err)
+	}
+
+	return dtos.UpdateContentBlockResponseFromEntity(updatedBlock), nil
+}
+
+// GetRevisions lists the revisions of a content block, most recent first.
+func (s *ContentBlocksService) GetRevisions(ctx context.Context, dto *dtos.GetRevisionsRequest) (*dtos.GetRevisionsResponse, error) {
+	contentBlock, err := s.findBlock(ctx, entities.NewBlockKey(dto.Page, dto.Section, dto.Locale))
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.repo.FindRevisions(ctx, contentBlock.ID)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgBlockRevisionResource), err)
+	}
+
+	return dtos.GetRevisionsResponseFromEntity(contentBlock, revisions), nil
+}
+
+// DiffRevisions compares the content of two revisions of a content block line by line.
+func (s *ContentBlocksService) DiffRevisions(ctx context.Context, dto *dtos.DiffRevisionsRequest) (*dtos.RevisionDiffDto, error) {
+	contentBlock, err := s.findBlock(ctx, entities.NewBlockKey(dto.Page, dto.Section, dto.Locale))
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := s.findRevision(ctx, contentBlock, dto.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findRevision(ctx, contentBlock, dto.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return dtos.RevisionDiffDtoFromLines(from.Revision, to.Revision, diff.Lines(from.Content, to.Content)), nil
+}
+
+// RollbackRevision restores the content of a past revision of a content block. The restored content is saved as a new
+// revision, so that the history is never rewritten and the rollback can itself be undone.
+func (s *ContentBlocksService) RollbackRevision(ctx context.Context, dto *dtos.RollbackRevisionRequest) (*dtos.UpdateContentBlockResponse, error) {
+	contentBlock, err := s.findAtVersion(ctx, entities.NewBlockKey(dto.Page, dto.Section, dto.Locale), dto.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.findRevision(ctx, contentBlock, dto.Revision)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureBaseline(ctx, contentBlock); err != nil {
+		return nil, err
+	}
+
+	contentBlock.Content = target.Content
+	if target.Format != "" && contentBlock.Type == "" {
+		contentBlock.Format = target.Format
+	}
+	// Revisions may predate sanitization or the current schema of the block type
+	if err := s.checkContent(ctx, contentBlock); err != nil {
+		return nil, err
+	}
+	updatedBlock, err := s.saveRevised(ctx, contentBlock, dto.AuthorID, &target.Revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return dtos.UpdateContentBlockResponseFromEntity(updatedBlock), nil
+}
+
+// checkContent rejects the content a block cannot hold: structured content that does not satisfy the schema of the
+// block type, and HTML holding markup the sanitization policy does not allow, listed under the content field. HTML is
+// otherwise saved as written, while structured content is indented so that its revisions diff line by line. Markdown
+// and text need no check since they are rendered safely. The includes of unstructured content are checked too, see
+// checkIncludes.
+func (s *ContentBlocksService) checkContent(ctx context.Context, contentBlock *entities.ContentBlocks) error {
+	switch {
+	case contentBlock.Type != "":
+		if err := s.blockTypes.ValidateContent(ctx, contentBlock.Type, contentBlock.Content); err != nil {
+			return err
+		}
+
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, []byte(contentBlock.Content), "", "  "); err == nil {
+			contentBlock.Content = indented.String()
+		}
+	case contentBlock.Format == constants.BlockFormatHTML:
+		if _, rejected := s.sanitizer.Sanitize(contentBlock.Content); len(rejected) > 0 {
+			return errors.ValidationErrors(map[string]string{
+				"Content": loc.L(msgkey.ErrMarkupNotAllowed, strings.Join(rejected, ", ")),
+			})
+		}
+	}
+
+	if contentBlock.Format != constants.BlockFormatJSON {
+		return s.checkIncludes(ctx, contentBlock)
+	}
+	return nil
+}
+
+// SanitizeBlocks removes the markup the sanitization policy does not allow from the HTML content, draft and published,
+// of every stored block, e.g. blocks written before sanitization existed or after the policy was tightened. A sanitized
+// draft is saved as a new revision. Unless the request applies the changes, the blocks are only reported.
+func (s *ContentBlocksService) SanitizeBlocks(ctx context.Context, dto *dtos.SanitizeBlocksRequest) (*dtos.SanitizeBlocksResponse, error) {
+	contentBlocks, err := s.repo.GetAllContentBlocks(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+
+	response := &dtos.SanitizeBlocksResponse{Applied: dto.Apply, Blocks: []dtos.SanitizedBlockDto{}}
+	for _, contentBlock := range contentBlocks {
+		report := dtos.SanitizedBlockDtoFromEntity(contentBlock)
+		sanitized := *contentBlock
+		if contentBlock.Format == constants.BlockFormatHTML {
+			sanitized.Content, report.DraftRejected = s.sanitizer.Sanitize(contentBlock.Content)
+		}
+		if contentBlock.IsPublished() && contentBlock.PublishedFormat == constants.BlockFormatHTML {
+			sanitized.PublishedContent, report.PublishedRejected = s.sanitizer.Sanitize(contentBlock.PublishedContent)
+		}
+		if len(report.DraftRejected) == 0 && len(report.PublishedRejected) == 0 {
+			continue
+		}
+
+		if dto.Apply {
+			if err := s.saveSanitized(ctx, contentBlock, &sanitized); err != nil {
+				report.Error = err.Error()
+			}
+		}
+		response.Blocks = append(response.Blocks, report)
+	}
+
+	return response, nil
+}
+
+// SearchBlocks finds the variants whose draft or published content, page or section match a full-text query, most
+// relevant first, with the passage of their content around the matched words.
+func (s *ContentBlocksService) SearchBlocks(ctx context.Context, dto *dtos.SearchBlocksRequest) (*dtos.SearchBlocksResponse, error) {
+	hits, totalCount, err := s.repo.Search(ctx, dto.Query, strings.ToLower(dto.Locale), dto.Page, dto.PageSize)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+
+	terms := textsearch.Terms(dto.Query)
+	contentBlocks := make([]dtos.SearchBlockDto, 0, len(hits))
+	for _, hit := range hits {
+		contentBlocks = append(contentBlocks, *dtos.SearchBlockDtoFromEntity(&hit.ContentBlocks, hit.Score, terms))
+	}
+
+	return &dtos.SearchBlocksResponse{Blocks: contentBlocks, Total: int(totalCount)}, nil
+}
+
+// ReindexBlocks recomputes the stored files every block refers to and the text it is searched by, e.g. for blocks
+// written before they were kept or referring to files uploaded after them, and reports the blocks whose derived data
+// changed.
+func (s *ContentBlocksService) ReindexBlocks(ctx context.Context) (*dtos.ReindexBlocksResponse, error) {
+	contentBlocks, err := s.repo.GetAllContentBlocks(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+
+	response := &dtos.ReindexBlocksResponse{Blocks: []dtos.ReindexedBlockDto{}}
+	for _, contentBlock := range contentBlocks {
+		changed, err := s.repo.Reindex(ctx, contentBlock)
+		if err == nil && !changed {
+			continue
+		}
+
+		report := dtos.ReindexedBlockDtoFromEntity(contentBlock)
+		if err != nil {
+			report.Error = err.Error()
+		}
+		response.Blocks = append(response.Blocks, report)
+	}
+
+	return response, nil
+}
+
+// saveSanitized saves the sanitized copy of a block, as a new revision when its draft changed. The content stored until
+// then is kept as the baseline revision of blocks that have none, so that it can still be reviewed.
+func (s *ContentBlocksService) saveSanitized(ctx context.Context, stored, sanitized *entities.ContentBlocks) error {
+	if sanitized.Content == stored.Content {
+		_, err := s.repo.UpdateContentBlock(ctx, sanitized)
+		return err
+	}
+
+	if err := s.ensureBaseline(ctx, stored); err != nil {
+		return err
+	}
+	_, err := s.saveRevised(ctx, sanitized, "", nil)
+	return err
+}
+
+// saveRevised records the next revision of a content block, then saves the block at that version. Recording the
+// revision first makes the unique revision number act as a lock between concurrent writes.
+func (s *ContentBlocksService) saveRevised(ctx context.Context, contentBlock *entities.ContentBlocks, authorID string, restoredFrom *int64) (*entities.ContentBlocks, error) {
+	revision := newRevision(contentBlock, authorID, restoredFrom)
+	revision.Revision = contentBlock.Version + 1
+	if err := s.repo.CreateRevision(ctx, revision); err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, s.concurrentWriteError(ctx, contentBlock.Key, err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgBlockRevisionResource), err)
+	}
+
+	updatedBlock, err := s.repo.UpdateContentBlock(ctx, contentBlock)
+	if err != nil {
+		if deleteErr := s.repo.DeleteRevision(ctx, revision); deleteErr != nil {
+			log.Printf("Error deleting revision %d of content block %s after failing to save it: %v", revision.Revision, contentBlock.ID.Hex(), deleteErr)
+		}
+		if database.IsNotFound(err) {
+			return nil, s.concurrentWriteError(ctx, contentBlock.Key, err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgContentBlockResource), err)
+	}
+
+	return updatedBlock, nil
+}
+
+// ensureBaseline records the current content of a block written before revisions were kept, so that it can be rolled
+// back to once it is updated.
+func (s *ContentBlocksService) ensureBaseline(ctx context.Context, contentBlock *entities.ContentBlocks) error {
+	count, err := s.repo.CountRevisions(ctx, contentBlock.ID)
+	if err != nil {
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgBlockRevisionResource), err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if err := s.repo.CreateRevision(ctx, newRevision(contentBlock, "", nil)); err != nil && !database.IsDuplicateKey(err) {
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgBlockRevisionResource), err)
+	}
+	return nil
+}
+
+// findBlock loads a content block, reporting a missing one as not found.
+func (s *ContentBlocksService) findBlock(ctx context.Context, key entities.BlockKey) (*entities.ContentBlocks, error) {
+	contentBlock, err := s.repo.GetContentBlock(ctx, key)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+	if contentBlock == nil {
+		return nil, errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgContentBlockResource), nil)
+	}
+	return contentBlock, nil
+}
+
+func (s *ContentBlocksService) findRevision(ctx context.Context, contentBlock *entities.ContentBlocks, number int64) (*entities.ContentBlockRevision, error) {
+	revision, err := s.repo.FindRevision(ctx, contentBlock.ID, number)
+	if err != nil {
+		if database.IsNotFound(err) {
+			return nil, errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgBlockRevisionResource), err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgBlockRevisionResource), err)
+	}
+	return revision, nil
+}
+
+// newRevision copies the current content of a block into a revision numbered after its current version. The revision
+// belongs to the tenant of the block, also when it is written outside a request scoped to that tenant.
+func newRevision(contentBlock *entities.ContentBlocks, authorID string, restoredFrom *int64) *entities.ContentBlockRevision {
+	return &entities.ContentBlockRevision{
+		ID:           idgenerator.GenerateID(),
+		TenantID:     contentBlock.TenantID,
+		BlockID:      contentBlock.ID,
+		Revision:     contentBlock.Version,
+		Content:      contentBlock.Content,
+		Format:       contentBlock.Format,
+		AuthorID:     authorID,
+		RestoredFrom: restoredFrom,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// findAtVersion loads a content block and checks that it is still at the version the client expects.
+func (s *ContentBlocksService) findAtVersion(ctx context.Context, key entities.BlockKey, expectedVersion int64) (*entities.ContentBlocks, error) {
+	contentBlock, err := s.findBlock(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !etag.Matches(expectedVersion, contentBlock.Version) {
+		return nil, errors.PreconditionFailed(contentBlock.Version)
+	}
+	return contentBlock, nil
+}
+
+// concurrentWriteError explains why a versioned write matched no block: either it was modified since it was read, in
+// which case its current version is reported, or it was deleted.
+func (s *ContentBlocksService) concurrentWriteError(ctx context.Context, key entities.BlockKey, err error) error {
+	current, findErr := s.repo.GetContentBlock(ctx, key)
+	if findErr != nil || current == nil {
+		return errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgContentBlockResource), err)
+	}
+	return errors.PreconditionFailed(current.Version)
 }
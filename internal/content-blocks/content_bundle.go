@@ -0,0 +1,449 @@
+package blocks
+
+import (
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/entities"
+	"company-name/internal/content-blocks/dtos"
+	"company-name/pkg/database"
+	"company-name/pkg/errors"
+	"company-name/pkg/idgenerator"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/utils/diff"
+	"context"
+	goerrors "errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportBundle gathers the metadata and the blocks, in every locale, of the requested pages into a bundle that can be
+// imported into another environment.
+func (s *ContentBlocksService) ExportBundle(ctx context.Context, dto *dtos.ExportBundleRequest) (*dtos.ContentBundle, error) {
+	slugs := dto.Pages
+	if len(slugs) == 0 {
+		var err error
+		if slugs, err = s.allPageSlugs(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	bundle := &dtos.ContentBundle{
+		Version:    constants.ContentBundleVersion,
+		ExportedAt: time.Now(),
+		Pages:      make([]dtos.BundlePageDto, 0, len(slugs)),
+	}
+	for _, slug := range slugs {
+		pageMetadata, err := s.pages.FindBySlug(ctx, slug)
+		if err != nil && !database.IsNotFound(err) {
+			return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgPageResource), err)
+		}
+
+		variants, err := s.repo.GetPageContentBlocks(ctx, slug)
+		if err != nil {
+			return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+		}
+		if pageMetadata == nil && len(variants) == 0 {
+			return nil, errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgPageResource), nil)
+		}
+
+		// Sorted so that bundles of the same content compare equal
+		slices.SortFunc(variants, func(a, b *entities.ContentBlocks) int {
+			return compareKeys(a.Key, b.Key)
+		})
+
+		bundledPage := dtos.BundlePageDto{
+			Slug:     slug,
+			Metadata: dtos.BundlePageMetadataDtoFromEntity(pageMetadata),
+			Blocks:   make([]dtos.BundleBlockDto, 0, len(variants)),
+		}
+		for _, variant := range variants {
+			bundledPage.Blocks = append(bundledPage.Blocks, dtos.BundleBlockDtoFromEntity(variant))
+		}
+		bundle.Pages = append(bundle.Pages, bundledPage)
+	}
+
+	return bundle, nil
+}
+
+// allPageSlugs lists, by slug, the pages that have metadata or blocks.
+func (s *ContentBlocksService) allPageSlugs(ctx context.Context) ([]string, error) {
+	pages, err := s.pages.FindAll(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgPageResource), err)
+	}
+	contentBlocks, err := s.repo.GetAllContentBlocks(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+
+	var slugs []string
+	for _, pageMetadata := range pages {
+		slugs = append(slugs, pageMetadata.Slug)
+	}
+	for _, contentBlock := range contentBlocks {
+		slugs = append(slugs, contentBlock.Key.Page)
+	}
+	slices.Sort(slugs)
+	return slices.Compact(slugs), nil
+}
+
+// ImportBundle makes the pages of a bundle match it: their metadata and blocks are created or updated, and in replace
+// mode the blocks, and the metadata, that the bundle lacks are deleted. Pages the bundle does not hold are left
+// untouched. Changed drafts are recorded as revisions attributed to the importer.
+//
+// The bundle is checked as a whole first, every invalid record being reported, then applied in a single transaction so
+// that a failure leaves the content as it was. A dry run only reports the changes.
+func (s *ContentBlocksService) ImportBundle(ctx context.Context, dto *dtos.ImportBundleRequest) (*dtos.ImportBundleResponse, error) {
+	if dto.Version != constants.ContentBundleVersion {
+		return nil, errors.ValidationErrors(map[string]string{
+			"Version": loc.L(msgkey.ErrBundleVersionUnsupported, strconv.Itoa(constants.ContentBundleVersion)),
+		})
+	}
+
+	if dto.DryRun {
+		plan, err := s.planImport(ctx, dto)
+		if err != nil {
+			return nil, err
+		}
+		return plan.response, nil
+	}
+
+	// The plan is made within the transaction, which may be retried, so that it is applied to the content it was made for
+	var response *dtos.ImportBundleResponse
+	err := s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		plan, err := s.planImport(ctx, dto)
+		if err != nil {
+			return err
+		}
+		for _, operation := range plan.operations {
+			if err := operation(ctx); err != nil {
+				return err
+			}
+		}
+		response = plan.response
+		return nil
+	})
+	if err != nil {
+		var httpError errors.HttpError
+		if goerrors.As(err, &httpError) {
+			return nil, err
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgContentBundleResource), err)
+	}
+
+	// Pages read while the transaction was open may have been cached with the content it replaced
+	for _, bundledPage := range dto.Pages {
+		s.pageCache.Invalidate(contextTenant(ctx), bundledPage.Slug)
+	}
+
+	return response, nil
+}
+
+// importPlan holds the changes an import makes and the operations making them.
+type importPlan struct {
+	response   *dtos.ImportBundleResponse
+	operations []func(ctx context.Context) error
+	invalid    map[string]string // Validation errors of the bundle, by path of the invalid field
+}
+
+func (p *importPlan) add(change dtos.BundleChangeDto, operation func(ctx context.Context) error) {
+	p.response.Add(change)
+	p.operations = append(p.operations, operation)
+}
+
+// reject records the validation errors of a bundled record under its path. Other errors are returned as they are.
+func (p *importPlan) reject(path string, err error) error {
+	fields := validationErrors(err)
+	if fields == nil {
+		return err
+	}
+	for field, message := range fields {
+		p.invalid[path+"."+field] = message
+	}
+	return nil
+}
+
+// validationErrors returns the errors by field of a validation error, nil for any other error.
+func validationErrors(err error) map[string]string {
+	var baseError *errors.BaseError
+	if goerrors.As(err, &baseError) && len(baseError.ValidationErrors()) > 0 {
+		return baseError.ValidationErrors()
+	}
+	return nil
+}
+
+// planImport compares the bundle with the stored content and lists the operations bringing the content in line with
+// it. The bundle is rejected when any of its records is invalid.
+func (s *ContentBlocksService) planImport(ctx context.Context, dto *dtos.ImportBundleRequest) (*importPlan, error) {
+	plan := &importPlan{
+		response: &dtos.ImportBundleResponse{Mode: dto.Mode, DryRun: dto.DryRun, Changes: []dtos.BundleChangeDto{}},
+		invalid:  map[string]string{},
+	}
+
+	seen := map[string]bool{}
+	for i, bundledPage := range dto.Pages {
+		path := fmt.Sprintf("Pages[%d]", i)
+		if err := s.validator.ValidateStruct(&bundledPage); err != nil {
+			if err := plan.reject(path, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if seen[bundledPage.Slug] {
+			plan.invalid[path+".Slug"] = loc.L(msgkey.ErrDuplicatedInBundle)
+			continue
+		}
+		seen[bundledPage.Slug] = true
+
+		if err := s.planPageMetadata(ctx, plan, path, &bundledPage, dto.Mode); err != nil {
+			return nil, err
+		}
+		if err := s.planPageBlocks(ctx, plan, path, &bundledPage, dto); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(plan.invalid) > 0 {
+		return nil, errors.ValidationErrors(plan.invalid)
+	}
+	return plan, nil
+}
+
+// planPageMetadata creates or updates the metadata of a bundled page, or deletes it in replace mode when the bundle has
+// none.
+func (s *ContentBlocksService) planPageMetadata(ctx context.Context, plan *importPlan, path string, bundledPage *dtos.BundlePageDto, mode string) error {
+	stored, err := s.pages.FindBySlug(ctx, bundledPage.Slug)
+	if err != nil && !database.IsNotFound(err) {
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgPageResource), err)
+	}
+	change := dtos.BundleChangeDto{Record: constants.BundleRecordPage, Page: bundledPage.Slug}
+
+	switch {
+	case bundledPage.Metadata == nil:
+		if stored == nil || mode != constants.ImportModeReplace {
+			return nil
+		}
+		change.Change = constants.BundleChangeDeleted
+		plan.add(change, func(ctx context.Context) error {
+			return s.pages.Delete(ctx, stored.Slug)
+		})
+
+	case stored == nil:
+		now := time.Now()
+		created := &entities.Page{ID: idgenerator.GenerateID(), Slug: bundledPage.Slug, CreatedAt: now, UpdatedAt: now}
+		bundledPage.Metadata.ApplyTo(created)
+		if err := s.validator.ValidateStruct(created); err != nil {
+			return plan.reject(path+".Metadata", err)
+		}
+
+		change.Change = constants.BundleChangeCreated
+		plan.add(change, func(ctx context.Context) error {
+			return s.pages.Create(ctx, created)
+		})
+
+	default:
+		updated := *stored
+		change.Fields = bundledPage.Metadata.ApplyTo(&updated)
+		if len(change.Fields) == 0 {
+			return nil
+		}
+		if err := s.validator.ValidateStruct(&updated); err != nil {
+			return plan.reject(path+".Metadata", err)
+		}
+
+		updated.UpdatedAt = time.Now()
+		change.Change = constants.BundleChangeUpdated
+		plan.add(change, func(ctx context.Context) error {
+			return s.pages.Update(ctx, &updated)
+		})
+	}
+	return nil
+}
+
+// planPageBlocks creates or updates the bundled blocks of a page, and in replace mode deletes its blocks the bundle
+// lacks. Blocks go through the checks of blocks written through the API, on their draft and published content.
+func (s *ContentBlocksService) planPageBlocks(ctx context.Context, plan *importPlan, pagePath string, bundledPage *dtos.BundlePageDto, dto *dtos.ImportBundleRequest) error {
+	variants, err := s.repo.GetPageContentBlocks(ctx, bundledPage.Slug)
+	if err != nil {
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+	stored := make(map[entities.BlockKey]*entities.ContentBlocks, len(variants))
+	for _, variant := range variants {
+		stored[variant.Key] = variant
+	}
+
+	bundled := map[entities.BlockKey]bool{}
+	for i, bundledBlock := range bundledPage.Blocks {
+		path := fmt.Sprintf("%s.Blocks[%d]", pagePath, i)
+		if err := s.validator.ValidateStruct(&bundledBlock); err != nil {
+			if err := plan.reject(path, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := entities.NewBlockKey(bundledPage.Slug, bundledBlock.Section, bundledBlock.Locale)
+		if bundled[key] {
+			plan.invalid[path+".Section"] = loc.L(msgkey.ErrDuplicatedInBundle)
+			continue
+		}
+		bundled[key] = true
+
+		target := &entities.ContentBlocks{Key: key}
+		if existing := stored[key]; existing != nil {
+			copied := *existing
+			target = &copied
+		}
+		bundledBlock.ApplyTo(target)
+		if err := s.checkImportedBlock(ctx, target); err != nil {
+			if err := plan.reject(path, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		change := dtos.BundleChangeDto{
+			Record:  constants.BundleRecordBlock,
+			Page:    key.Page,
+			Section: key.Section,
+			Locale:  key.Locale,
+		}
+		existing := stored[key]
+		if existing == nil {
+			change.Change = constants.BundleChangeCreated
+			plan.add(change, func(ctx context.Context) error {
+				return s.createImportedBlock(ctx, target, dto.AuthorID)
+			})
+			continue
+		}
+
+		change.Fields = dtos.BlockChanges(existing, target)
+		if len(change.Fields) == 0 {
+			continue
+		}
+		if existing.Content != target.Content {
+			change.Diff = dtos.DiffLineDtosFromLines(diff.Lines(existing.Content, target.Content))
+		}
+		change.Change = constants.BundleChangeUpdated
+		plan.add(change, func(ctx context.Context) error {
+			return s.updateImportedBlock(ctx, existing, target, dto.AuthorID)
+		})
+	}
+
+	if dto.Mode != constants.ImportModeReplace {
+		return nil
+	}
+	for _, variant := range variants {
+		if bundled[variant.Key] {
+			continue
+		}
+		deleted := variant
+		plan.add(dtos.BundleChangeDto{
+			Record:  constants.BundleRecordBlock,
+			Change:  constants.BundleChangeDeleted,
+			Page:    deleted.Key.Page,
+			Section: deleted.Key.Section,
+			Locale:  deleted.Key.Locale,
+		}, func(ctx context.Context) error {
+			if err := s.repo.DeleteContentBlock(ctx, deleted); err != nil {
+				return s.importWriteError(ctx, deleted.Key, err)
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// checkImportedBlock validates an imported block and checks its draft and published content, which are normalized
+// like the content written through the API.
+func (s *ContentBlocksService) checkImportedBlock(ctx context.Context, contentBlock *entities.ContentBlocks) error {
+	if err := contentBlock.Validate(s.validator); err != nil {
+		return err
+	}
+	if err := s.checkContent(ctx, contentBlock); err != nil {
+		return err
+	}
+	if !contentBlock.IsPublished() {
+		return nil
+	}
+
+	// Errors of the published content are reported under its own field, e.g. PublishedContent#/title
+	published := contentBlock.PublishedView()
+	if err := s.checkContent(ctx, published); err != nil {
+		fields := validationErrors(err)
+		if fields == nil {
+			return err
+		}
+		publishedFields := make(map[string]string, len(fields))
+		for field, message := range fields {
+			publishedFields["Published"+field] = message
+		}
+		return errors.ValidationErrors(publishedFields)
+	}
+	contentBlock.PublishedContent = published.Content
+	return nil
+}
+
+// createImportedBlock creates a block with its first revision.
+func (s *ContentBlocksService) createImportedBlock(ctx context.Context, contentBlock *entities.ContentBlocks, authorID string) error {
+	if contentBlock.IsPublished() {
+		now := time.Now()
+		contentBlock.PublishedAt = &now
+	}
+
+	createdBlock, err := s.repo.CreateContentBlock(ctx, contentBlock)
+	if err != nil {
+		if database.IsDuplicateKey(err) {
+			return errors.Conflict(err)
+		}
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgContentBlockResource), err)
+	}
+
+	if err := s.repo.CreateRevision(ctx, newRevision(createdBlock, authorID, nil)); err != nil {
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgBlockRevisionResource), err)
+	}
+	return nil
+}
+
+// updateImportedBlock saves an imported block over the stored one, as a new revision when its draft changed.
+func (s *ContentBlocksService) updateImportedBlock(ctx context.Context, stored, imported *entities.ContentBlocks, authorID string) error {
+	if imported.IsPublished() && (!stored.IsPublished() || imported.PublishedContent != stored.PublishedContent || imported.PublishedFormat != stored.PublishedFormat) {
+		now := time.Now()
+		imported.PublishedAt = &now
+	}
+
+	if imported.Content == stored.Content && imported.Format == stored.Format {
+		if _, err := s.repo.UpdateContentBlock(ctx, imported); err != nil {
+			return s.importWriteError(ctx, imported.Key, err)
+		}
+		return nil
+	}
+
+	if err := s.ensureBaseline(ctx, stored); err != nil {
+		return err
+	}
+	_, err := s.saveRevised(ctx, imported, authorID, nil)
+	return err
+}
+
+// importWriteError reports a failed write of an imported block like the writes of the API do.
+func (s *ContentBlocksService) importWriteError(ctx context.Context, key entities.BlockKey, err error) error {
+	if database.IsNotFound(err) {
+		return s.concurrentWriteError(ctx, key, err)
+	}
+	return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgContentBlockResource), err)
+}
+
+// compareKeys orders block keys by page, section, then locale.
+func compareKeys(a, b entities.BlockKey) int {
+	if a.Page != b.Page {
+		return strings.Compare(a.Page, b.Page)
+	}
+	if a.Section != b.Section {
+		return strings.Compare(a.Section, b.Section)
+	}
+	return strings.Compare(a.Locale, b.Locale)
+}
@@ -0,0 +1,28 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"time"
+)
+
+// BlockEventDto is the data of an event streamed when a variant of a block of a page is written, see
+// constants.BlockEventCreated and the following.
+type BlockEventDto struct {
+	Page      string    `json:"page"`
+	Section   string    `json:"section"`
+	Locale    string    `json:"locale"`
+	Status    string    `json:"status"`
+	Version   int64     `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func BlockEventDtoFromEntity(contentBlock *entities.ContentBlocks) *BlockEventDto {
+	return &BlockEventDto{
+		Page:      contentBlock.Key.Page,
+		Section:   contentBlock.Key.Section,
+		Locale:    contentBlock.Key.Locale,
+		Status:    contentBlock.Status,
+		Version:   contentBlock.Version,
+		UpdatedAt: contentBlock.UpdatedAt,
+	}
+}
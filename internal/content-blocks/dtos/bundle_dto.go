@@ -0,0 +1,219 @@
+package dtos
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/utils/diff"
+	"slices"
+	"time"
+)
+
+// ContentBundle holds pages and their blocks, to be exported from an environment and imported into another. Only the
+// content is carried: identifiers, versions, revisions and scheduled publishing stay with each environment.
+type ContentBundle struct {
+	Version    int             `json:"version" validate:"required"` // Version of the bundle format, see constants.ContentBundleVersion
+	ExportedAt time.Time       `json:"exported_at"`
+	Pages      []BundlePageDto `json:"pages" validate:"required"`
+}
+
+type BundlePageDto struct {
+	Slug     string                 `json:"slug" validate:"required"`
+	Metadata *BundlePageMetadataDto `json:"metadata,omitempty"` // Missing for pages whose metadata was never written
+	Blocks   []BundleBlockDto       `json:"blocks"`
+}
+
+type BundlePageMetadataDto struct {
+	Title           string   `json:"title"`
+	MetaDescription string   `json:"meta_description"`
+	OGImage         string   `json:"og_image"`
+	Sections        []string `json:"sections"`
+	Tags            []string `json:"tags,omitempty"`
+}
+
+// BundleBlockDto is a variant of a block of a bundled page. Its content may be written as structured data for typed
+// blocks, like in requests.
+type BundleBlockDto struct {
+	Section          string       `json:"section" validate:"required"`
+	Locale           string       `json:"locale"` // Default language when empty
+	Format           string       `json:"format" validate:"omitempty,oneof=html markdown text json"`
+	Type             string       `json:"type,omitempty"`
+	Content          BlockContent `json:"content" validate:"required"`
+	Status           string       `json:"status" validate:"omitempty,oneof=draft published"` // Draft when empty
+	PublishedContent BlockContent `json:"published_content,omitempty"`                       // Draft content when empty for a published block
+	PublishedFormat  string       `json:"published_format,omitempty"`
+}
+
+// ExportBundleRequest lists the pages to export, every page when empty.
+type ExportBundleRequest struct {
+	Pages []string
+}
+
+// ImportBundleRequest applies a bundle as exported, in the given mode. A dry run only reports the changes.
+type ImportBundleRequest struct {
+	ContentBundle
+	Mode     string `json:"-" validate:"required,oneof=upsert replace"`
+	DryRun   bool   `json:"-"`
+	AuthorID string `json:"-"`
+}
+
+// BundleChangeDto is a change made, or that would be made, by an import. Changed blocks report their fields that
+// differ and, when their draft content does, how it changes line by line.
+type BundleChangeDto struct {
+	Record  string        `json:"record"` // See constants.BundleRecordPage and BundleRecordBlock
+	Change  string        `json:"change"`
+	Page    string        `json:"page"`
+	Section string        `json:"section,omitempty"`
+	Locale  string        `json:"locale,omitempty"`
+	Fields  []string      `json:"fields,omitempty"`
+	Diff    []DiffLineDto `json:"diff,omitempty"`
+}
+
+type ImportBundleResponse struct {
+	Mode    string            `json:"mode"`
+	DryRun  bool              `json:"dry_run"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Deleted int               `json:"deleted"`
+	Changes []BundleChangeDto `json:"changes"`
+}
+
+// Add records a change, counting it by kind.
+func (res *ImportBundleResponse) Add(change BundleChangeDto) {
+	switch change.Change {
+	case constants.BundleChangeCreated:
+		res.Created++
+	case constants.BundleChangeUpdated:
+		res.Updated++
+	case constants.BundleChangeDeleted:
+		res.Deleted++
+	}
+	res.Changes = append(res.Changes, change)
+}
+
+func BundlePageMetadataDtoFromEntity(page *entities.Page) *BundlePageMetadataDto {
+	if page == nil {
+		return nil
+	}
+	return &BundlePageMetadataDto{
+		Title:           page.Title,
+		MetaDescription: page.MetaDescription,
+		OGImage:         page.OGImage,
+		Sections:        page.Sections,
+		Tags:            page.Tags,
+	}
+}
+
+// ApplyTo writes the metadata onto a page, reporting the fields whose value changed.
+func (dto *BundlePageMetadataDto) ApplyTo(page *entities.Page) []string {
+	var fields []string
+	if page.Title != dto.Title {
+		page.Title = dto.Title
+		fields = append(fields, "title")
+	}
+	if page.MetaDescription != dto.MetaDescription {
+		page.MetaDescription = dto.MetaDescription
+		fields = append(fields, "meta_description")
+	}
+	if page.OGImage != dto.OGImage {
+		page.OGImage = dto.OGImage
+		fields = append(fields, "og_image")
+	}
+
+	sections := dto.Sections
+	if sections == nil {
+		sections = []string{}
+	}
+	if !slices.Equal(page.Sections, sections) {
+		page.Sections = sections
+		fields = append(fields, "sections")
+	}
+
+	tags := entities.NormalizeTags(dto.Tags)
+	if !slices.Equal(page.Tags, tags) {
+		page.Tags = tags
+		fields = append(fields, "tags")
+	}
+	return fields
+}
+
+func BundleBlockDtoFromEntity(contentBlock *entities.ContentBlocks) BundleBlockDto {
+	dto := BundleBlockDto{
+		Section: contentBlock.Key.Section,
+		Locale:  contentBlock.Key.Locale,
+		Format:  contentBlock.Format,
+		Type:    contentBlock.Type,
+		Content: BlockContent(contentBlock.Content),
+		Status:  contentBlock.Status,
+	}
+	if contentBlock.IsPublished() {
+		dto.PublishedContent = BlockContent(contentBlock.PublishedContent)
+		dto.PublishedFormat = contentBlock.PublishedFormat
+	}
+	return dto
+}
+
+// ApplyTo writes the bundled variant onto a block. Its content is only normalized by the checks the block goes through
+// afterwards, so the block is compared with the stored one once checked, see BlockChanges.
+func (dto *BundleBlockDto) ApplyTo(contentBlock *entities.ContentBlocks) {
+	contentBlock.Content = string(dto.Content)
+	contentBlock.Type = dto.Type
+	contentBlock.Format = dto.Format
+	switch {
+	case dto.Type != "":
+		contentBlock.Format = constants.BlockFormatJSON
+	case dto.Format == "":
+		contentBlock.Format = constants.BlockFormatHTML
+	}
+
+	if dto.Status != constants.BlockStatusPublished {
+		contentBlock.Status = constants.BlockStatusDraft
+		contentBlock.PublishedContent = ""
+		contentBlock.PublishedFormat = ""
+		return
+	}
+
+	contentBlock.Status = constants.BlockStatusPublished
+	contentBlock.PublishedContent = string(dto.PublishedContent)
+	contentBlock.PublishedFormat = dto.PublishedFormat
+	if dto.PublishedContent == "" {
+		contentBlock.PublishedContent = contentBlock.Content
+	}
+	if contentBlock.PublishedFormat == "" || dto.Type != "" {
+		contentBlock.PublishedFormat = contentBlock.Format
+	}
+}
+
+// BlockChanges lists the fields of a block that differ between its stored and imported versions.
+func BlockChanges(stored, imported *entities.ContentBlocks) []string {
+	var fields []string
+	if stored.Content != imported.Content {
+		fields = append(fields, "content")
+	}
+	if stored.Format != imported.Format {
+		fields = append(fields, "format")
+	}
+	if stored.Type != imported.Type {
+		fields = append(fields, "type")
+	}
+	if stored.Status != imported.Status {
+		fields = append(fields, "status")
+	}
+	if stored.PublishedContent != imported.PublishedContent {
+		fields = append(fields, "published_content")
+	}
+	if stored.PublishedFormat != imported.PublishedFormat {
+		fields = append(fields, "published_format")
+	}
+	return fields
+}
+
+// DiffLineDtosFromLines converts a line diff, leaving out the unchanged lines.
+func DiffLineDtosFromLines(lines []diff.Line) []DiffLineDto {
+	var dtos []DiffLineDto
+	for _, line := range lines {
+		if line.Op != diff.OpEqual {
+			dtos = append(dtos, DiffLineDto{Op: line.Op, Text: line.Text})
+		}
+	}
+	return dtos
+}
@@ -1,35 +1,108 @@
 package dtos
 
 import (
+	"company-name/constants"
 	"company-name/entities"
+	"encoding/json"
 	"time"
 )
 
+// BlockContent is the content of a block in a request: a JSON string for content written in a markup format, or any
+// other JSON value, kept as its JSON text, for the structured content of typed blocks.
+type BlockContent string
+
+func (c *BlockContent) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch content := value.(type) {
+	case nil:
+		*c = ""
+	case string:
+		*c = BlockContent(content)
+	default:
+		*c = BlockContent(data)
+	}
+	return nil
+}
+
+// contentValue returns the content of a block as served: structured content as a JSON value, any other content as a
+// string.
+func contentValue(content, format string) interface{} {
+	if format == constants.BlockFormatJSON && json.Valid([]byte(content)) {
+		return json.RawMessage(content)
+	}
+	return content
+}
+
+// contentText returns the stored form of a content served by contentValue.
+func contentText(content interface{}) string {
+	switch value := content.(type) {
+	case string:
+		return value
+	case json.RawMessage:
+		return string(value)
+	}
+	return ""
+}
+
 type ContentBlockDto struct {
-	Page      string    `json:"page"`
-	Section   string    `json:"section"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Page        string      `json:"page"`
+	Section     string      `json:"section"`
+	Locale      string      `json:"locale"`
+	Content     interface{} `json:"content"` // A JSON value for typed blocks, a string otherwise
+	Format      string      `json:"format"`
+	Type        string      `json:"type,omitempty"`
+	Version     int64       `json:"version"`
+	Status      string      `json:"status"`
+	PublishedAt *time.Time  `json:"published_at,omitempty"`
+	PublishAt   *time.Time  `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time  `json:"unpublish_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
 }
 
 func ContentBlockDtoFromEntity(entity *entities.ContentBlocks) *ContentBlockDto {
 	return &ContentBlockDto{
-		Page:      entity.Key.Page,
-		Section:   entity.Key.Section,
-		Content:   entity.Content,
-		CreatedAt: entity.CreatedAt,
-		UpdatedAt: entity.UpdatedAt,
+		Page:        entity.Key.Page,
+		Section:     entity.Key.Section,
+		Locale:      entity.Key.Locale,
+		Content:     contentValue(entity.Content, entity.Format),
+		Format:      entity.Format,
+		Type:        entity.Type,
+		Version:     entity.Version,
+		Status:      entity.Status,
+		PublishedAt: entity.PublishedAt,
+		PublishAt:   entity.PublishAt,
+		UnpublishAt: entity.UnpublishAt,
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}
+}
+
+// InLocation renders the timestamps of the block in the given location.
+func (dto *ContentBlockDto) InLocation(location *time.Location) {
+	dto.CreatedAt = dto.CreatedAt.In(location)
+	dto.UpdatedAt = dto.UpdatedAt.In(location)
+	dto.PublishedAt = optionalIn(dto.PublishedAt, location)
+	dto.PublishAt = optionalIn(dto.PublishAt, location)
+	dto.UnpublishAt = optionalIn(dto.UnpublishAt, location)
+}
+
+func optionalIn(timestamp *time.Time, location *time.Location) *time.Time {
+	if timestamp == nil {
+		return nil
 	}
+	inLocation := timestamp.In(location)
+	return &inLocation
 }
 
 func (dto *ContentBlockDto) ToEntity() *entities.ContentBlocks {
 	return &entities.ContentBlocks{
-		Key: entities.BlockKey{
-			Page:    dto.Page,
-			Section: dto.Section,
-		},
-		Content:   dto.Content,
+		Key:       entities.NewBlockKey(dto.Page, dto.Section, dto.Locale),
+		Content:   contentText(dto.Content),
 		CreatedAt: dto.CreatedAt,
 		UpdatedAt: dto.UpdatedAt,
 	}
@@ -1,23 +1,36 @@
 package dtos
 
 import (
+	"company-name/constants"
 	"company-name/entities"
 	"time"
 )
 
 type CreateContentBlockRequest struct {
-	Page    string `json:"page" validate:"required"`
-	Section string `json:"section" validate:"required"`
-	Content string `json:"content" validate:"required"`
+	Page     string       `json:"page" validate:"required"`
+	Section  string       `json:"section" validate:"required"`
+	Locale   string       `json:"locale" validate:"omitempty,alpha,len=2"`
+	Content  BlockContent `json:"content" validate:"required"`
+	Format   string       `json:"format" validate:"omitempty,oneof=html markdown text"` // HTML when omitted, ignored for typed blocks
+	Type     string       `json:"type" validate:"omitempty,max=100"`                    // Makes the content a JSON document of that block type
+	AuthorID string       `json:"-"`
 }
 
 func (req *CreateContentBlockRequest) ToEntity() *entities.ContentBlocks {
+	format := req.Format
+	switch {
+	case req.Type != "":
+		format = constants.BlockFormatJSON
+	case format == "":
+		format = constants.BlockFormatHTML
+	}
+
 	return &entities.ContentBlocks{
-		Key: entities.BlockKey{
-			Page:    req.Page,
-			Section: req.Section,
-		},
-		Content:   req.Content,
+		Key:       entities.NewBlockKey(req.Page, req.Section, req.Locale),
+		Content:   string(req.Content),
+		Format:    format,
+		Type:      req.Type,
+		Status:    constants.BlockStatusDraft,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -1,6 +1,8 @@
 package dtos
 
 type DeleteBlockRequest struct {
-	Page    string `json:"page"`
-	Section string `json:"section"`
+	Page            string `form:"page" json:"page" validate:"required"`
+	Section         string `form:"section" json:"section" validate:"required"`
+	Locale          string `form:"locale" json:"locale" validate:"omitempty,alpha,len=2"`
+	ExpectedVersion int64  `form:"-" json:"-"`
 }
@@ -0,0 +1,36 @@
+package dtos
+
+import "company-name/entities"
+
+type BlockDependentsRequest struct {
+	Page    string `form:"page" validate:"required"`
+	Section string `form:"section" validate:"required"`
+}
+
+// BlockDependentDto is a variant of a block that shows the requested block, by including it at depth 1 or by including
+// a block that does at greater depths.
+type BlockDependentDto struct {
+	Page     string `json:"page"`
+	Section  string `json:"section"`
+	Locale   string `json:"locale"`
+	Status   string `json:"status"`
+	Depth    int    `json:"depth"`
+	Includes string `json:"includes"` // Block it includes on the way to the requested one, as "page/section"
+}
+
+type BlockDependentsResponse struct {
+	Page       string              `json:"page"`
+	Section    string              `json:"section"`
+	Dependents []BlockDependentDto `json:"dependents"`
+}
+
+func BlockDependentDtoFromEntity(entity *entities.ContentBlocks, depth int, includes string) BlockDependentDto {
+	return BlockDependentDto{
+		Page:     entity.Key.Page,
+		Section:  entity.Key.Section,
+		Locale:   entity.Key.Locale,
+		Status:   entity.Status,
+		Depth:    depth,
+		Includes: includes,
+	}
+}
@@ -3,8 +3,12 @@ package dtos
 import "company-name/entities"
 
 type GetContentBlockRequest struct {
-	Page    string `form:"page" validate:"required"`
-	Section string `form:"section" validate:"required"`
+	Page    string   `form:"page" validate:"required"`
+	Section string   `form:"section" validate:"required"`
+	Locale  string   `form:"locale" validate:"omitempty,alpha,len=2"`
+	Draft   bool     `form:"draft"`                                           // Reserved to authenticated editors, public reads only see published content
+	Render  string   `form:"render" validate:"omitempty,oneof=raw html text"` // HTML when omitted
+	Locales []string `form:"-"`                                               // Locales to serve in order of preference, see localization.FallbackChain
 }
 
 type GetContentBlockResponse struct {
@@ -1,29 +1,59 @@
 package dtos
 
-import "company-name/entities"
+import (
+	"company-name/entities"
+	pagedtos "company-name/internal/page/dtos"
+	"time"
+)
 
 type GetPageContentBlocksRequest struct {
-	Page string `form:"page" validate:"required"`
+	Page    string   `form:"page" validate:"required"`
+	Draft   bool     `form:"draft"`                                           // Reserved to authenticated editors, public reads only see published content
+	Render  string   `form:"render" validate:"omitempty,oneof=raw html text"` // HTML when omitted
+	Locales []string `form:"-"`                                               // Locales to serve in order of preference, see localization.FallbackChain
 }
 
 type GetPageContentBlocksResponse struct {
-	Blocks []ContentBlockDto
+	Page         *pagedtos.PageDto `json:"page,omitempty"` // Missing for pages whose metadata was never written
+	Blocks       []ContentBlockDto
+	ETag         string    `json:"-"` // Validator of the response, changing whenever the page, one of its blocks or what they include does
+	LastModified time.Time `json:"-"` // Latest update of the page or of one of its blocks, zero when the page is empty
 }
 
-func GetPageContentBlocksResponseFromEntity(blocks []*entities.ContentBlocks) *GetPageContentBlocksResponse {
+func GetPageContentBlocksResponseFromEntity(page *entities.Page, blocks []*entities.ContentBlocks) *GetPageContentBlocksResponse {
 	var blocksDto []ContentBlockDto
 
 	for _, block := range blocks {
-		blocksDto = append(blocksDto, ContentBlockDto{
-			Page:      block.Key.Page,
-			Section:   block.Key.Section,
-			Content:   block.Content,
-			CreatedAt: block.CreatedAt,
-			UpdatedAt: block.UpdatedAt,
-		})
+		blocksDto = append(blocksDto, *ContentBlockDtoFromEntity(block))
 	}
 
-	return &GetPageContentBlocksResponse{
+	response := &GetPageContentBlocksResponse{
 		Blocks: blocksDto,
 	}
+	if page != nil {
+		response.Page = pagedtos.PageDtoFromEntity(page)
+	}
+	return response
+}
+
+// InLocation renders the timestamps of the page and of every block in the given location.
+func (res *GetPageContentBlocksResponse) InLocation(location *time.Location) {
+	if res.Page != nil {
+		res.Page.InLocation(location)
+	}
+	for i := range res.Blocks {
+		res.Blocks[i].InLocation(location)
+	}
+}
+
+// Clone returns a copy of the response that can be put in another location without changing the original, which may
+// be shared through the page cache.
+func (res *GetPageContentBlocksResponse) Clone() *GetPageContentBlocksResponse {
+	clone := *res
+	if res.Page != nil {
+		page := *res.Page
+		clone.Page = &page
+	}
+	clone.Blocks = append([]ContentBlockDto(nil), res.Blocks...)
+	return &clone
 }
@@ -0,0 +1,53 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"slices"
+)
+
+type MissingTranslationsRequest struct {
+	Locale string `form:"locale" validate:"required,alpha,len=2"`
+	Page   string `form:"page"` // Optional, every page is checked when empty
+}
+
+// MissingTranslationDto is a block without a variant in the requested locale, with the locales it is available in.
+type MissingTranslationDto struct {
+	Page    string   `json:"page"`
+	Section string   `json:"section"`
+	Locales []string `json:"locales"`
+}
+
+type MissingTranslationsResponse struct {
+	Locale   string                  `json:"locale"`
+	Sections []MissingTranslationDto `json:"sections"`
+}
+
+// MissingTranslationsResponseFromEntity groups the variants by block and lists the blocks none of whose variants is
+// in the given locale, in the order they were first found.
+func MissingTranslationsResponseFromEntity(locale string, variants []*entities.ContentBlocks) *MissingTranslationsResponse {
+	type section struct{ page, section string }
+
+	var order []section
+	locales := map[section][]string{}
+	for _, variant := range variants {
+		key := section{variant.Key.Page, variant.Key.Section}
+		if _, ok := locales[key]; !ok {
+			order = append(order, key)
+		}
+		locales[key] = append(locales[key], variant.Key.Locale)
+	}
+
+	response := &MissingTranslationsResponse{Locale: locale, Sections: []MissingTranslationDto{}}
+	for _, key := range order {
+		if slices.Contains(locales[key], locale) {
+			continue
+		}
+		slices.Sort(locales[key])
+		response.Sections = append(response.Sections, MissingTranslationDto{
+			Page:    key.page,
+			Section: key.section,
+			Locales: locales[key],
+		})
+	}
+	return response
+}
@@ -0,0 +1,21 @@
+package dtos
+
+import "time"
+
+// PublishBlockRequest publishes the draft of a block, immediately or at PublishAt when it is in the future.
+type PublishBlockRequest struct {
+	Page            string     `json:"page" validate:"required"`
+	Section         string     `json:"section" validate:"required"`
+	Locale          string     `json:"locale" validate:"omitempty,alpha,len=2"`
+	PublishAt       *time.Time `json:"publish_at"`
+	ExpectedVersion int64      `json:"-"`
+}
+
+// UnpublishBlockRequest hides a block from the public, immediately or at UnpublishAt when it is in the future.
+type UnpublishBlockRequest struct {
+	Page            string     `json:"page" validate:"required"`
+	Section         string     `json:"section" validate:"required"`
+	Locale          string     `json:"locale" validate:"omitempty,alpha,len=2"`
+	UnpublishAt     *time.Time `json:"unpublish_at"`
+	ExpectedVersion int64      `json:"-"`
+}
@@ -0,0 +1,31 @@
+package dtos
+
+import "company-name/entities"
+
+// ReindexedBlockDto is a block whose derived data changed once recomputed from its stored content.
+type ReindexedBlockDto struct {
+	TenantID string `json:"tenant_id,omitempty"`
+	Page     string `json:"page"`
+	Section  string `json:"section"`
+	Locale   string `json:"locale"`
+	Files    int    `json:"files"`           // Stored files the content refers to
+	Error    string `json:"error,omitempty"` // Why the derived data could not be saved
+}
+
+type ReindexBlocksResponse struct {
+	Blocks []ReindexedBlockDto `json:"blocks"`
+}
+
+// ReindexedBlockDtoFromEntity identifies a block in a reindexing report.
+func ReindexedBlockDtoFromEntity(contentBlock *entities.ContentBlocks) ReindexedBlockDto {
+	dto := ReindexedBlockDto{
+		Page:    contentBlock.Key.Page,
+		Section: contentBlock.Key.Section,
+		Locale:  contentBlock.Key.Locale,
+		Files:   len(contentBlock.FileIDs),
+	}
+	if contentBlock.TenantID != nil {
+		dto.TenantID = contentBlock.TenantID.Hex()
+	}
+	return dto
+}
@@ -0,0 +1,105 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"company-name/pkg/utils/diff"
+	"time"
+)
+
+type GetRevisionsRequest struct {
+	Page    string `form:"page" validate:"required"`
+	Section string `form:"section" validate:"required"`
+	Locale  string `form:"locale" validate:"omitempty,alpha,len=2"`
+}
+
+type DiffRevisionsRequest struct {
+	Page    string `form:"page" validate:"required"`
+	Section string `form:"section" validate:"required"`
+	Locale  string `form:"locale" validate:"omitempty,alpha,len=2"`
+	From    int64  `form:"from" validate:"required,min=1"`
+	To      int64  `form:"to" validate:"required,min=1"`
+}
+
+type RollbackRevisionRequest struct {
+	Page            string `json:"page" validate:"required"`
+	Section         string `json:"section" validate:"required"`
+	Locale          string `json:"locale" validate:"omitempty,alpha,len=2"`
+	Revision        int64  `json:"revision" validate:"required,min=1"`
+	AuthorID        string `json:"-"`
+	ExpectedVersion int64  `json:"-"`
+}
+
+type RevisionDto struct {
+	Revision     int64       `json:"revision"`
+	Content      interface{} `json:"content"` // A JSON value for typed blocks, a string otherwise
+	Format       string      `json:"format"`
+	AuthorID     string      `json:"author_id"`
+	RestoredFrom *int64      `json:"restored_from,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+func RevisionDtoFromEntity(entity *entities.ContentBlockRevision) *RevisionDto {
+	return &RevisionDto{
+		Revision:     entity.Revision,
+		Content:      contentValue(entity.Content, entity.Format),
+		Format:       entity.Format,
+		AuthorID:     entity.AuthorID,
+		RestoredFrom: entity.RestoredFrom,
+		CreatedAt:    entity.CreatedAt,
+	}
+}
+
+type GetRevisionsResponse struct {
+	Page      string        `json:"page"`
+	Section   string        `json:"section"`
+	Locale    string        `json:"locale"`
+	Revisions []RevisionDto `json:"revisions"`
+}
+
+func GetRevisionsResponseFromEntity(block *entities.ContentBlocks, revisions []*entities.ContentBlockRevision) *GetRevisionsResponse {
+	dtos := make([]RevisionDto, 0, len(revisions))
+	for _, revision := range revisions {
+		dtos = append(dtos, *RevisionDtoFromEntity(revision))
+	}
+	return &GetRevisionsResponse{Page: block.Key.Page, Section: block.Key.Section, Locale: block.Key.Locale, Revisions: dtos}
+}
+
+// InLocation renders the timestamps of the revisions in the given location.
+func (dto *GetRevisionsResponse) InLocation(location *time.Location) {
+	for i := range dto.Revisions {
+		dto.Revisions[i].CreatedAt = dto.Revisions[i].CreatedAt.In(location)
+	}
+}
+
+type DiffLineDto struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+type RevisionDiffDto struct {
+	From    int64         `json:"from"`
+	To      int64         `json:"to"`
+	Added   int           `json:"added"`
+	Removed int           `json:"removed"`
+	Lines   []DiffLineDto `json:"lines"`
+	Unified string        `json:"unified"`
+}
+
+func RevisionDiffDtoFromLines(from, to int64, lines []diff.Line) *RevisionDiffDto {
+	dto := &RevisionDiffDto{
+		From:    from,
+		To:      to,
+		Lines:   make([]DiffLineDto, 0, len(lines)),
+		Unified: diff.Unified(lines),
+	}
+	for _, line := range lines {
+		switch line.Op {
+		case diff.OpInsert:
+			dto.Added++
+		case diff.OpDelete:
+			dto.Removed++
+		}
+		dto.Lines = append(dto.Lines, DiffLineDto{Op: line.Op, Text: line.Text})
+	}
+	return dto
+}
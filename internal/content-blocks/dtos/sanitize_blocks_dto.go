@@ -0,0 +1,37 @@
+package dtos
+
+import "company-name/entities"
+
+// SanitizeBlocksRequest re-sanitizes the stored blocks, only reporting the changes unless Apply is set.
+type SanitizeBlocksRequest struct {
+	Apply bool
+}
+
+// SanitizedBlockDto is a block whose stored content holds markup the sanitization policy does not allow.
+type SanitizedBlockDto struct {
+	TenantID          string   `json:"tenant_id,omitempty"`
+	Page              string   `json:"page"`
+	Section           string   `json:"section"`
+	Locale            string   `json:"locale"`
+	DraftRejected     []string `json:"draft_rejected,omitempty"`     // Markup removed from the draft content
+	PublishedRejected []string `json:"published_rejected,omitempty"` // Markup removed from the published content
+	Error             string   `json:"error,omitempty"`              // Why the sanitized block could not be saved
+}
+
+type SanitizeBlocksResponse struct {
+	Applied bool                `json:"applied"`
+	Blocks  []SanitizedBlockDto `json:"blocks"`
+}
+
+// SanitizedBlockDtoFromEntity identifies a block in a sanitization report.
+func SanitizedBlockDtoFromEntity(contentBlock *entities.ContentBlocks) SanitizedBlockDto {
+	dto := SanitizedBlockDto{
+		Page:    contentBlock.Key.Page,
+		Section: contentBlock.Key.Section,
+		Locale:  contentBlock.Key.Locale,
+	}
+	if contentBlock.TenantID != nil {
+		dto.TenantID = contentBlock.TenantID.Hex()
+	}
+	return dto
+}
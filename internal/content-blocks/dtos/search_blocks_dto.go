@@ -0,0 +1,62 @@
+package dtos
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/utils/textsearch"
+	"time"
+)
+
+type SearchBlocksRequest struct {
+	Query    string `form:"q" validate:"required,max=100" binding:"required"`
+	Locale   string `form:"locale" validate:"omitempty,alpha,len=2"` // Every locale when empty
+	Page     int    `form:"page" validate:"required,min=1" binding:"required"`
+	PageSize int    `form:"page_size" validate:"required,min=1,max=100" binding:"required"`
+}
+
+// SearchBlockDto is a matching variant along with its relevance, the passage of its content around the matched words
+// and, for its page and section when they contain a matched word, their value with those words highlighted.
+type SearchBlockDto struct {
+	Page       string            `json:"page"`
+	Section    string            `json:"section"`
+	Locale     string            `json:"locale"`
+	Status     string            `json:"status"`
+	Score      float64           `json:"score"`
+	Snippet    string            `json:"snippet"`
+	Highlights map[string]string `json:"highlights"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+type SearchBlocksResponse struct {
+	Blocks []SearchBlockDto `json:"blocks"`
+	Total  int              `json:"total"`
+}
+
+func SearchBlockDtoFromEntity(contentBlock *entities.ContentBlocks, score float64, terms []string) *SearchBlockDto {
+	snippet, _ := textsearch.Snippet(contentBlock.SearchText, terms, constants.SearchSnippetRadius)
+
+	highlights := make(map[string]string)
+	for field, value := range map[string]string{"page": contentBlock.Key.Page, "section": contentBlock.Key.Section} {
+		if highlighted, matched := textsearch.Highlight(value, terms); matched {
+			highlights[field] = highlighted
+		}
+	}
+
+	return &SearchBlockDto{
+		Page:       contentBlock.Key.Page,
+		Section:    contentBlock.Key.Section,
+		Locale:     contentBlock.Key.Locale,
+		Status:     contentBlock.Status,
+		Score:      score,
+		Snippet:    snippet,
+		Highlights: highlights,
+		UpdatedAt:  contentBlock.UpdatedAt,
+	}
+}
+
+// InLocation renders the timestamps of the matching variants in the given location.
+func (res *SearchBlocksResponse) InLocation(location *time.Location) {
+	for i := range res.Blocks {
+		res.Blocks[i].UpdatedAt = res.Blocks[i].UpdatedAt.In(location)
+	}
+}
@@ -1,24 +1,34 @@
 package dtos
 
 import (
+	"company-name/constants"
 	"company-name/entities"
-	"time"
 )
 
 type UpdateContentBlockRequest struct {
-	Page    string `json:"page" validate:"required"`
-	Section string `json:"section" validate:"required"`
-	Content string `json:"content" validate:"required"`
+	Page            string       `json:"page" validate:"required"`
+	Section         string       `json:"section" validate:"required"`
+	Locale          string       `json:"locale" validate:"omitempty,alpha,len=2"`
+	Content         BlockContent `json:"content" validate:"required"`
+	Format          string       `json:"format" validate:"omitempty,oneof=html markdown text"` // Unchanged when omitted, ignored for typed blocks
+	Type            string       `json:"type" validate:"omitempty,max=100"`                    // Unchanged when omitted
+	AuthorID        string       `json:"-"`
+	ExpectedVersion int64        `json:"-"`
 }
 
-func (req *UpdateContentBlockRequest) ToEntity() *entities.ContentBlocks {
-	return &entities.ContentBlocks{
-		Key: entities.BlockKey{
-			Page:    req.Page,
-			Section: req.Section,
-		},
-		Content:   req.Content,
-		UpdatedAt: time.Now(),
+// ApplyTo copies the updated content, and its format or type when given, onto the stored block. Typed blocks remain
+// typed, their content being JSON.
+func (req *UpdateContentBlockRequest) ApplyTo(block *entities.ContentBlocks) {
+	block.Content = string(req.Content)
+	if req.Type != "" {
+		block.Type = req.Type
+	}
+
+	switch {
+	case block.Type != "":
+		block.Format = constants.BlockFormatJSON
+	case req.Format != "":
+		block.Format = req.Format
 	}
 }
 
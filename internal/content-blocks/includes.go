@@ -0,0 +1,285 @@
+package blocks
+
+import (
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/entities"
+	"company-name/internal/content-blocks/dtos"
+	"company-name/internal/variable"
+	"company-name/pkg/database"
+	"company-name/pkg/errors"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/render"
+	"context"
+	"fmt"
+	"hash"
+	"html"
+	"log"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// includeRef returns how a block is referred to by the includes of other blocks.
+func includeRef(page, section string) string {
+	return page + "/" + section
+}
+
+// expander replaces the includes and variables in the content of the blocks served by a read. The included blocks and
+// the variables are loaded once per read and kept, so that the response can be validated against them too.
+type expander struct {
+	repo      IContentBlockRepository
+	variables variable.IVariableRepository
+	locales   []string
+	draft     bool
+	included  map[string]*entities.ContentBlocks // Variant served of each included block, nil when it has none
+	values    map[string]*entities.Variable      // Nil for the variables that do not exist
+}
+
+func (s *ContentBlocksService) newExpander(locales []string, draft bool) *expander {
+	return &expander{
+		repo:      s.repo,
+		variables: s.variables,
+		locales:   locales,
+		draft:     draft,
+		included:  map[string]*entities.ContentBlocks{},
+		values:    map[string]*entities.Variable{},
+	}
+}
+
+// expand replaces the directives in the content of a block read. An included block is served in the same locales and
+// status as the block including it and is expanded in turn, up to MaxIncludeDepth levels; included in HTML, it is
+// rendered to HTML first, and variables are escaped. Structured content is left as written. Directives that cannot
+// be expanded, naming a block or variable that does not exist or nesting too deep, are replaced by nothing.
+func (e *expander) expand(ctx context.Context, contentBlock *entities.ContentBlocks) error {
+	stack := []string{includeRef(contentBlock.Key.Page, contentBlock.Key.Section)}
+	content, err := e.expandContent(ctx, contentBlock.Content, contentBlock.Format, stack)
+	if err != nil {
+		return err
+	}
+	contentBlock.Content = content
+	return nil
+}
+
+// expandContent expands content written in format, stack holding the blocks being expanded, outermost first.
+func (e *expander) expandContent(ctx context.Context, content, format string, stack []string) (string, error) {
+	if format == constants.BlockFormatJSON {
+		return content, nil
+	}
+
+	return render.ExpandDirectives(content, func(directive render.Directive) (string, error) {
+		if directive.Kind == render.DirectiveVar {
+			value, err := e.variable(ctx, directive.Argument)
+			if err != nil || value == nil {
+				return "", err
+			}
+			if format == constants.BlockFormatHTML {
+				return html.EscapeString(value.Value), nil
+			}
+			return value.Value, nil
+		}
+
+		// Cycles are refused when blocks are written, but may come from blocks written before includes were checked
+		if slices.Contains(stack, directive.Argument) || len(stack) > constants.MaxIncludeDepth {
+			log.Printf("Error expanding the include of %s in %s: cycle or too deep", directive.Argument, stack[len(stack)-1])
+			return "", nil
+		}
+
+		included, err := e.block(ctx, directive.Argument)
+		if err != nil || included == nil {
+			return "", err
+		}
+
+		expanded, err := e.expandContent(ctx, included.Content, included.Format, append(stack, directive.Argument))
+		if err != nil {
+			return "", err
+		}
+		if format == constants.BlockFormatHTML {
+			return render.ToHTML(expanded, included.Format)
+		}
+		return expanded, nil
+	})
+}
+
+// block returns the variant to serve of an included block.
+func (e *expander) block(ctx context.Context, ref string) (*entities.ContentBlocks, error) {
+	if contentBlock, ok := e.included[ref]; ok {
+		return contentBlock, nil
+	}
+
+	var contentBlock *entities.ContentBlocks
+	if page, section, ok := render.ParseInclude(ref); ok {
+		variants, err := e.repo.GetSectionVariants(ctx, page, section)
+		if err != nil {
+			return nil, err
+		}
+		contentBlock = variantFor(variants, e.locales, e.draft)
+	}
+
+	e.included[ref] = contentBlock
+	return contentBlock, nil
+}
+
+func (e *expander) variable(ctx context.Context, name string) (*entities.Variable, error) {
+	if value, ok := e.values[name]; ok {
+		return value, nil
+	}
+
+	value, err := e.variables.FindByName(ctx, name)
+	if err != nil {
+		if !database.IsNotFound(err) {
+			return nil, err
+		}
+		value = nil
+	}
+
+	e.values[name] = value
+	return value, nil
+}
+
+// includedPages lists the pages of the blocks included, whether they exist or not.
+func (e *expander) includedPages() []string {
+	var pages []string
+	for ref := range e.included {
+		if page, _, ok := render.ParseInclude(ref); ok && !slices.Contains(pages, page) {
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
+
+// variableNames lists the variables referred to, whether they exist or not.
+func (e *expander) variableNames() []string {
+	return slices.Collect(maps.Keys(e.values))
+}
+
+// writeValidators writes the version of what was included to the hash of a response, and returns the latest update
+// among them.
+func (e *expander) writeValidators(hash hash.Hash) time.Time {
+	var lastModified time.Time
+	for _, ref := range slices.Sorted(maps.Keys(e.included)) {
+		included := e.included[ref]
+		if included == nil {
+			fmt.Fprintf(hash, "include|%s|-\n", ref)
+			continue
+		}
+		fmt.Fprintf(hash, "include|%s|%s|%d|%d\n", ref, included.ID.Hex(), included.Version, included.UpdatedAt.UnixNano())
+		if included.UpdatedAt.After(lastModified) {
+			lastModified = included.UpdatedAt
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(e.values)) {
+		value := e.values[name]
+		if value == nil {
+			fmt.Fprintf(hash, "var|%s|-\n", name)
+			continue
+		}
+		fmt.Fprintf(hash, "var|%s|%d\n", name, value.UpdatedAt.UnixNano())
+		if value.UpdatedAt.After(lastModified) {
+			lastModified = value.UpdatedAt
+		}
+	}
+	return lastModified
+}
+
+// checkIncludes rejects the includes of a block that are malformed, that would make it show itself, directly or
+// through the blocks it includes, or that nest deeper than MaxIncludeDepth, listed under the content field. Blocks that
+// do not exist yet may be included, they show nothing until they are written.
+func (s *ContentBlocksService) checkIncludes(ctx context.Context, contentBlock *entities.ContentBlocks) error {
+	self := includeRef(contentBlock.Key.Page, contentBlock.Key.Section)
+	contents := map[string][]string{} // Contents of the variants of the blocks visited, draft and published
+
+	var visit func(content, root string, depth int) error
+	visit = func(content, root string, depth int) error {
+		for _, directive := range render.Directives(content) {
+			if directive.Kind != render.DirectiveInclude {
+				continue
+			}
+
+			ref := directive.Argument
+			if depth == 1 {
+				root = ref
+			}
+			page, section, ok := render.ParseInclude(ref)
+			switch {
+			case !ok && depth == 1:
+				return errors.ValidationErrors(map[string]string{"Content": loc.L(msgkey.ErrInvalidInclude, ref)})
+			case !ok:
+				continue
+			case ref == self:
+				return errors.ValidationErrors(map[string]string{"Content": loc.L(msgkey.ErrIncludeCycle, root)})
+			case depth > constants.MaxIncludeDepth:
+				return errors.ValidationErrors(map[string]string{
+					"Content": loc.L(msgkey.ErrIncludeTooDeep, strconv.Itoa(constants.MaxIncludeDepth)),
+				})
+			}
+
+			if _, ok := contents[ref]; !ok {
+				variants, err := s.repo.GetSectionVariants(ctx, page, section)
+				if err != nil {
+					return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+				}
+				contents[ref] = []string{}
+				for _, variant := range variants {
+					if variant.Format != constants.BlockFormatJSON {
+						contents[ref] = append(contents[ref], variant.Content, variant.PublishedContent)
+					}
+				}
+			}
+			for _, included := range contents[ref] {
+				if err := visit(included, root, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	return visit(contentBlock.Content, "", 1)
+}
+
+// GetDependents lists the variants of the blocks that show a block by including it, directly or through other blocks,
+// closest first, so that editors know what changing it affects.
+func (s *ContentBlocksService) GetDependents(ctx context.Context, dto *dtos.BlockDependentsRequest) (*dtos.BlockDependentsResponse, error) {
+	response := &dtos.BlockDependentsResponse{Page: dto.Page, Section: dto.Section, Dependents: []dtos.BlockDependentDto{}}
+
+	target := includeRef(dto.Page, dto.Section)
+	visited := map[string]bool{target: true}
+	listed := map[string]bool{}
+	level := []string{target}
+	for depth := 1; depth <= constants.MaxIncludeDepth && len(level) > 0; depth++ {
+		var next []string
+		for _, ref := range level {
+			page, section, _ := strings.Cut(ref, "/")
+			including, err := s.repo.FindIncluding(ctx, page, section)
+			if err != nil {
+				return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+			}
+
+			for _, variant := range including {
+				if listed[variant.ID.Hex()] {
+					continue
+				}
+				listed[variant.ID.Hex()] = true
+				response.Dependents = append(response.Dependents, dtos.BlockDependentDtoFromEntity(variant, depth, ref))
+
+				dependent := includeRef(variant.Key.Page, variant.Key.Section)
+				if !visited[dependent] {
+					visited[dependent] = true
+					next = append(next, dependent)
+				}
+			}
+		}
+		level = next
+	}
+
+	slices.SortStableFunc(response.Dependents, func(a, b dtos.BlockDependentDto) int {
+		if a.Depth != b.Depth {
+			return a.Depth - b.Depth
+		}
+		return strings.Compare(a.Page+"\x00"+a.Section+"\x00"+a.Locale, b.Page+"\x00"+b.Section+"\x00"+b.Locale)
+	})
+	return response, nil
+}
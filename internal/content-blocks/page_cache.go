@@ -0,0 +1,232 @@
+package blocks
+
+import (
+	"company-name/entities"
+	"company-name/internal/content-blocks/dtos"
+	"company-name/internal/page"
+	"company-name/internal/variable"
+	"company-name/pkg/cache"
+	"company-name/pkg/database"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PageCache keeps the responses of GetPage in memory, per tenant, page and variant of the request. Entries are dropped
+// as soon as a block of their page, a block they include, a variable they refer to or the page itself is written
+// through the repositories returned by NewCachedContentBlockRepository, NewCachedPageRepository and
+// NewCachedVariableRepository; writes made by other processes are only seen once entries expire. A nil PageCache caches
+// nothing.
+type PageCache struct {
+	responses *cache.LRU[pageCacheKey, pageCacheEntry]
+}
+
+type pageCacheKey struct {
+	tenant  primitive.ObjectID // Nil for the platform itself
+	page    string
+	variant string // Draft, rendering and locales of the request
+}
+
+type pageCacheEntry struct {
+	response  *dtos.GetPageContentBlocksResponse
+	includes  []string // Pages of the blocks included by the blocks of the page, at any depth
+	variables []string // Variables the blocks of the page refer to, whether they exist or not
+}
+
+// NewPageCache initializes a cache holding up to size page responses, each for at most ttl.
+func NewPageCache(size int, ttl time.Duration) *PageCache {
+	return &PageCache{responses: cache.NewLRU[pageCacheKey, pageCacheEntry](size, ttl)}
+}
+
+// Get returns the response cached for the request, to be cloned before it is changed.
+func (c *PageCache) Get(ctx context.Context, dto *dtos.GetPageContentBlocksRequest) (*dtos.GetPageContentBlocksResponse, bool) {
+	if c == nil {
+		return nil, false
+	}
+	entry, ok := c.responses.Get(newPageCacheKey(ctx, dto))
+	return entry.response, ok
+}
+
+// Add caches the response of the request along with the pages whose blocks it includes and the variables it refers
+// to, whose writes invalidate it too.
+func (c *PageCache) Add(ctx context.Context, dto *dtos.GetPageContentBlocksRequest, response *dtos.GetPageContentBlocksResponse, includes, variables []string) {
+	if c == nil {
+		return
+	}
+	c.responses.Add(newPageCacheKey(ctx, dto), pageCacheEntry{response: response, includes: includes, variables: variables})
+}
+
+// Invalidate drops every response cached for a page of a tenant, nil for the platform itself, or including one of its
+// blocks.
+func (c *PageCache) Invalidate(tenantID *primitive.ObjectID, slug string) {
+	if c == nil {
+		return
+	}
+
+	tenant := tenantOrNil(tenantID)
+	c.responses.RemoveFunc(func(key pageCacheKey, entry pageCacheEntry) bool {
+		return key.tenant == tenant && (key.page == slug || slices.Contains(entry.includes, slug))
+	})
+}
+
+// InvalidateVariable drops every response cached for a tenant, nil for the platform itself, that refers to a variable.
+func (c *PageCache) InvalidateVariable(tenantID *primitive.ObjectID, name string) {
+	if c == nil {
+		return
+	}
+
+	tenant := tenantOrNil(tenantID)
+	c.responses.RemoveFunc(func(key pageCacheKey, entry pageCacheEntry) bool {
+		return key.tenant == tenant && slices.Contains(entry.variables, name)
+	})
+}
+
+func tenantOrNil(tenantID *primitive.ObjectID) primitive.ObjectID {
+	if tenantID != nil {
+		return *tenantID
+	}
+	return primitive.NilObjectID
+}
+
+func newPageCacheKey(ctx context.Context, dto *dtos.GetPageContentBlocksRequest) pageCacheKey {
+	tenant, _ := database.TenantFromContext(ctx)
+
+	variant := dto.Render + "|" + strings.Join(dto.Locales, ",")
+	if dto.Draft {
+		variant = "draft|" + variant
+	}
+	return pageCacheKey{tenant: tenant, page: dto.Page, variant: variant}
+}
+
+// contextTenant returns the tenant the context is scoped to, nil for the platform itself.
+func contextTenant(ctx context.Context) *primitive.ObjectID {
+	if tenantID, ok := database.TenantFromContext(ctx); ok {
+		return &tenantID
+	}
+	return nil
+}
+
+// pendingInvalidationsKey is the context key of the pages written in a transaction, invalidated once it commits.
+type pendingInvalidationsKey struct{}
+
+// CachedContentBlockRepository invalidates the cached page of every block it writes. Blocks carry their tenant, so
+// writes made across tenants, e.g. by the publish scheduler, invalidate the right page. Writes made in a transaction
+// invalidate their page once it commits, so that a read made meanwhile cannot cache the page as it was before.
+type CachedContentBlockRepository struct {
+	IContentBlockRepository
+	cache *PageCache
+}
+
+// NewCachedContentBlockRepository wraps repo so that its writes invalidate the pages cached in pageCache.
+func NewCachedContentBlockRepository(repo IContentBlockRepository, pageCache *PageCache) IContentBlockRepository {
+	return &CachedContentBlockRepository{IContentBlockRepository: repo, cache: pageCache}
+}
+
+func (r *CachedContentBlockRepository) CreateContentBlock(ctx context.Context, contentBlock *entities.ContentBlocks) (*entities.ContentBlocks, error) {
+	created, err := r.IContentBlockRepository.CreateContentBlock(ctx, contentBlock)
+	r.invalidate(ctx, contentBlock)
+	return created, err
+}
+
+func (r *CachedContentBlockRepository) UpdateContentBlock(ctx context.Context, contentBlock *entities.ContentBlocks) (*entities.ContentBlocks, error) {
+	updated, err := r.IContentBlockRepository.UpdateContentBlock(ctx, contentBlock)
+	r.invalidate(ctx, contentBlock)
+	return updated, err
+}
+
+func (r *CachedContentBlockRepository) DeleteContentBlock(ctx context.Context, contentBlock *entities.ContentBlocks) error {
+	err := r.IContentBlockRepository.DeleteContentBlock(ctx, contentBlock)
+	r.invalidate(ctx, contentBlock)
+	return err
+}
+
+// WithTransaction runs function in a transaction of the wrapped repository and invalidates the pages of the blocks
+// written in it once it commits. The pages of an attempt that is retried or rolled back are not invalidated.
+func (r *CachedContentBlockRepository) WithTransaction(ctx context.Context, function func(ctx context.Context) error) error {
+	var pending []func()
+	err := r.IContentBlockRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		pending = nil
+		return function(context.WithValue(ctx, pendingInvalidationsKey{}, &pending))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, invalidate := range pending {
+		invalidate()
+	}
+	return nil
+}
+
+// invalidate drops the cached page of a written block, or holds it back when the write is part of a transaction.
+func (r *CachedContentBlockRepository) invalidate(ctx context.Context, contentBlock *entities.ContentBlocks) {
+	tenantID, slug := contentBlock.TenantID, contentBlock.Key.Page
+	if pending, ok := ctx.Value(pendingInvalidationsKey{}).(*[]func()); ok {
+		*pending = append(*pending, func() { r.cache.Invalidate(tenantID, slug) })
+		return
+	}
+	r.cache.Invalidate(tenantID, slug)
+}
+
+// CachedPageRepository invalidates the cached page whose metadata it writes.
+type CachedPageRepository struct {
+	page.IPageRepository
+	cache *PageCache
+}
+
+// NewCachedPageRepository wraps repo so that its writes invalidate the pages cached in pageCache.
+func NewCachedPageRepository(repo page.IPageRepository, pageCache *PageCache) page.IPageRepository {
+	return &CachedPageRepository{IPageRepository: repo, cache: pageCache}
+}
+
+func (r *CachedPageRepository) Create(ctx context.Context, page *entities.Page) error {
+	err := r.IPageRepository.Create(ctx, page)
+	r.cache.Invalidate(page.TenantID, page.Slug)
+	return err
+}
+
+func (r *CachedPageRepository) Update(ctx context.Context, page *entities.Page) error {
+	err := r.IPageRepository.Update(ctx, page)
+	r.cache.Invalidate(page.TenantID, page.Slug)
+	return err
+}
+
+func (r *CachedPageRepository) Delete(ctx context.Context, slug string) error {
+	// Deleted pages are only known by their slug, they belong to the tenant of the context
+	err := r.IPageRepository.Delete(ctx, slug)
+	r.cache.Invalidate(contextTenant(ctx), slug)
+	return err
+}
+
+// CachedVariableRepository invalidates the cached pages referring to the variables it writes.
+type CachedVariableRepository struct {
+	variable.IVariableRepository
+	cache *PageCache
+}
+
+// NewCachedVariableRepository wraps repo so that its writes invalidate the pages cached in pageCache.
+func NewCachedVariableRepository(repo variable.IVariableRepository, pageCache *PageCache) variable.IVariableRepository {
+	return &CachedVariableRepository{IVariableRepository: repo, cache: pageCache}
+}
+
+func (r *CachedVariableRepository) Create(ctx context.Context, variable *entities.Variable) error {
+	err := r.IVariableRepository.Create(ctx, variable)
+	r.cache.InvalidateVariable(variable.TenantID, variable.Name)
+	return err
+}
+
+func (r *CachedVariableRepository) Update(ctx context.Context, variable *entities.Variable) error {
+	err := r.IVariableRepository.Update(ctx, variable)
+	r.cache.InvalidateVariable(variable.TenantID, variable.Name)
+	return err
+}
+
+func (r *CachedVariableRepository) Delete(ctx context.Context, name string) error {
+	// Deleted variables are only known by their name, they belong to the tenant of the context
+	err := r.IVariableRepository.Delete(ctx, name)
+	r.cache.InvalidateVariable(contextTenant(ctx), name)
+	return err
+}
@@ -0,0 +1,63 @@
+package blocks
+
+import (
+	"company-name/pkg/database"
+	"context"
+	"log"
+	"time"
+)
+
+// PublishScheduler publishes and unpublishes the content blocks whose scheduled time has come, for every tenant.
+type PublishScheduler struct {
+	repo     IContentBlockRepository
+	interval time.Duration
+}
+
+// NewPublishScheduler initializes a scheduler checking for due blocks at the given interval
+func NewPublishScheduler(repo IContentBlockRepository, interval time.Duration) *PublishScheduler {
+	return &PublishScheduler{
+		repo:     repo,
+		interval: interval,
+	}
+}
+
+// Run checks for due blocks until the context is cancelled.
+func (s *PublishScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunDue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue applies the publishing and unpublishing scheduled at or before now. A block edited concurrently is left for
+// the next run.
+func (s *PublishScheduler) RunDue(ctx context.Context, now time.Time) {
+	ctx = database.WithoutTenantScope(ctx)
+
+	contentBlocks, err := s.repo.FindDueForScheduling(ctx, now)
+	if err != nil {
+		log.Printf("Error finding content blocks due for publishing: %v", err)
+		return
+	}
+
+	for _, contentBlock := range contentBlocks {
+		if contentBlock.PublishAt != nil && !contentBlock.PublishAt.After(now) {
+			contentBlock.Publish(now)
+		}
+		if contentBlock.UnpublishAt != nil && !contentBlock.UnpublishAt.After(now) {
+			contentBlock.Unpublish()
+		}
+
+		if _, err := s.repo.UpdateContentBlock(ctx, contentBlock); err != nil && !database.IsNotFound(err) {
+			log.Printf("Error applying the scheduled publishing of content block %s: %v", contentBlock.ID.Hex(), err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package blocks
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/render"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// searchText strips the draft and published content of a block of their markup and directives, for the text index.
+// The published content is only added when it differs from the draft.
+func searchText(contentBlock *entities.ContentBlocks) string {
+	texts := []string{contentText(contentBlock.Content, contentBlock.Format)}
+	if contentBlock.IsPublished() && contentBlock.PublishedContent != contentBlock.Content {
+		texts = append(texts, contentText(contentBlock.PublishedContent, contentBlock.PublishedFormat))
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n"))
+}
+
+// contentText returns the text of content written in the given format. Structured JSON content gives the text of its
+// string values, markup stripped, one per line.
+func contentText(content, format string) string {
+	if format == constants.BlockFormatJSON {
+		var value interface{}
+		if err := json.Unmarshal([]byte(content), &value); err != nil {
+			return ""
+		}
+		var texts []string
+		collectStrings(value, func(text string) {
+			if text = contentText(text, constants.BlockFormatHTML); text != "" {
+				texts = append(texts, text)
+			}
+		})
+		return strings.Join(texts, "\n")
+	}
+
+	// Directives are replaced by the content they insert when read, which is indexed with its own block
+	content, _ = render.ExpandDirectives(content, func(render.Directive) (string, error) { return "", nil })
+	text, err := render.ToText(content, format)
+	if err != nil {
+		return content
+	}
+	return text
+}
+
+func collectStrings(value interface{}, collect func(string)) {
+	switch value := value.(type) {
+	case string:
+		collect(value)
+	case []interface{}:
+		for _, item := range value {
+			collectStrings(item, collect)
+		}
+	case map[string]interface{}:
+		// Sorted, so that the text of unchanged content stays the same
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			collectStrings(value[key], collect)
+		}
+	}
+}
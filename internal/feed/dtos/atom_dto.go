@@ -0,0 +1,37 @@
+package dtos
+
+import "encoding/xml"
+
+// AtomNamespace is the namespace of Atom feeds, see RFC 4287.
+const AtomNamespace = "http://www.w3.org/2005/Atom"
+
+// AtomFeed lists the news pages of the site, most recently updated first.
+type AtomFeed struct {
+	XMLName   xml.Name    `xml:"feed"`
+	Namespace string      `xml:"xmlns,attr"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Author    AtomPerson  `xml:"author"`
+	Links     []AtomLink  `xml:"link"`
+	Entries   []AtomEntry `xml:"entry"`
+}
+
+type AtomPerson struct {
+	Name string `xml:"name"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type AtomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Updated   string     `xml:"updated"`
+	Published string     `xml:"published,omitempty"`
+	Summary   string     `xml:"summary,omitempty"`
+	Links     []AtomLink `xml:"link"`
+}
@@ -0,0 +1,26 @@
+package dtos
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// SitemapNamespace is the namespace of sitemap documents, see https://www.sitemaps.org/protocol.html.
+const SitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// Sitemap lists the published pages of the site.
+type Sitemap struct {
+	XMLName   xml.Name     `xml:"urlset"`
+	Namespace string       `xml:"xmlns,attr"`
+	URLs      []SitemapURL `xml:"url"`
+}
+
+type SitemapURL struct {
+	Location     string `xml:"loc"`
+	LastModified string `xml:"lastmod,omitempty"` // W3C datetime, see FormatTime
+}
+
+// FormatTime formats a time the way sitemaps and Atom feeds expect it.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,129 @@
+package feed
+
+import (
+	"company-name/configs"
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/entities"
+	"company-name/internal/content-blocks"
+	"company-name/internal/feed/dtos"
+	"company-name/internal/page"
+	"company-name/pkg/errors"
+	loc "company-name/pkg/localization"
+	"context"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// IFeedService describes the published pages of the site for crawlers and feed readers: a sitemap of every published
+// page and an Atom feed of the pages tagged as news.
+type IFeedService interface {
+	GetSitemap(ctx context.Context) (*dtos.Sitemap, error)
+	GetNewsFeed(ctx context.Context) (*dtos.AtomFeed, error)
+}
+
+type Service struct {
+	blocks blocks.IContentBlockRepository
+	pages  page.IPageRepository
+	config *configs.Config
+}
+
+// NewFeedService initializes a new feed service. Links point to the site at configs.Config.App.SiteUrl.
+func NewFeedService(blocks blocks.IContentBlockRepository, pages page.IPageRepository, config *configs.Config) IFeedService {
+	return &Service{
+		blocks: blocks,
+		pages:  pages,
+		config: config,
+	}
+}
+
+// GetSitemap lists the pages having at least one published block, by slug, each last modified when the most recent of
+// its published blocks was updated.
+func (s *Service) GetSitemap(ctx context.Context) (*dtos.Sitemap, error) {
+	modified, err := s.publishedPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sitemap := &dtos.Sitemap{Namespace: dtos.SitemapNamespace, URLs: []dtos.SitemapURL{}}
+	for _, slug := range slices.Sorted(maps.Keys(modified)) {
+		sitemap.URLs = append(sitemap.URLs, dtos.SitemapURL{
+			Location:     s.pageURL(slug),
+			LastModified: dtos.FormatTime(modified[slug]),
+		})
+	}
+	return sitemap, nil
+}
+
+// GetNewsFeed lists the published pages tagged as news, most recently updated first, up to constants.NewsFeedSize.
+// A page is updated when the most recent of its published blocks was.
+func (s *Service) GetNewsFeed(ctx context.Context) (*dtos.AtomFeed, error) {
+	modified, err := s.publishedPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := s.pages.FindByTag(ctx, constants.NewsPageTag)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgPageResource), err)
+	}
+
+	pages = slices.DeleteFunc(pages, func(page *entities.Page) bool {
+		_, published := modified[page.Slug]
+		return !published
+	})
+	slices.SortStableFunc(pages, func(a, b *entities.Page) int {
+		return modified[b.Slug].Compare(modified[a.Slug])
+	})
+	pages = pages[:min(len(pages), constants.NewsFeedSize)]
+
+	siteURL := strings.TrimSuffix(s.config.App.SiteUrl, "/")
+	feed := &dtos.AtomFeed{
+		Namespace: dtos.AtomNamespace,
+		ID:        siteURL + "/",
+		Title:     s.config.App.Name,
+		Author:    dtos.AtomPerson{Name: s.config.App.Name},
+		Links:     []dtos.AtomLink{{Href: siteURL + "/"}},
+		Entries:   []dtos.AtomEntry{},
+	}
+
+	// An empty feed was last updated when it is read, the most recent entry dates a feed listing some
+	updated := time.Now()
+	if len(pages) > 0 {
+		updated = modified[pages[0].Slug]
+	}
+	feed.Updated = dtos.FormatTime(updated)
+
+	for _, page := range pages {
+		link := s.pageURL(page.Slug)
+		entry := dtos.AtomEntry{
+			ID:      link,
+			Title:   page.Title,
+			Updated: dtos.FormatTime(modified[page.Slug]),
+			Summary: page.MetaDescription,
+			Links:   []dtos.AtomLink{{Href: link}},
+		}
+		if !page.CreatedAt.IsZero() {
+			entry.Published = dtos.FormatTime(page.CreatedAt)
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed, nil
+}
+
+// publishedPages returns the pages having at least one published block, with the time the most recent of their
+// published blocks was updated.
+func (s *Service) publishedPages(ctx context.Context) (map[string]time.Time, error) {
+	modified, err := s.blocks.FindPublishedPages(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+	return modified, nil
+}
+
+// pageURL returns the link of a page on the public site.
+func (s *Service) pageURL(slug string) string {
+	return strings.TrimSuffix(s.config.App.SiteUrl, "/") + "/" + url.PathEscape(slug)
+}
@@ -0,0 +1,31 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"time"
+)
+
+type FileDto struct {
+	ID           string    `json:"id"`
+	Path         string    `json:"path"`
+	OriginalName string    `json:"original_name"`
+	MimeType     string    `json:"mime_type"`
+	Size         int64     `json:"size"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func FileDtoFromEntity(entity *entities.File) *FileDto {
+	return &FileDto{
+		ID:           entity.ID.Hex(),
+		Path:         entity.Path,
+		OriginalName: entity.OriginalName,
+		MimeType:     entity.MimeType,
+		Size:         entity.Size,
+		CreatedAt:    entity.CreatedAt,
+	}
+}
+
+// InLocation renders the timestamps of the file in the given location.
+func (dto *FileDto) InLocation(location *time.Location) {
+	dto.CreatedAt = dto.CreatedAt.In(location)
+}
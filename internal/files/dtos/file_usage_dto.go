@@ -0,0 +1,45 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"time"
+)
+
+// DeleteFileRequest identifies the stored file to delete.
+type DeleteFileRequest struct {
+	ID string `json:"-" validate:"required"`
+}
+
+// FileUsageDto is a variant of a content block whose draft or published content refers to a file, or a user whose
+// avatar it is.
+type FileUsageDto struct {
+	Page    string `json:"page,omitempty"`
+	Section string `json:"section,omitempty"`
+	Locale  string `json:"locale,omitempty"`
+	UserID  string `json:"user_id,omitempty"`
+}
+
+func FileUsageDtoFromEntity(contentBlock *entities.ContentBlocks) FileUsageDto {
+	return FileUsageDto{
+		Page:    contentBlock.Key.Page,
+		Section: contentBlock.Key.Section,
+		Locale:  contentBlock.Key.Locale,
+	}
+}
+
+func FileUsageDtoFromUser(user *entities.User) FileUsageDto {
+	return FileUsageDto{UserID: user.ID.Hex()}
+}
+
+// UnreferencedFilesResponse lists the stored files that no content block refers to and no user has as avatar, most
+// recent first.
+type UnreferencedFilesResponse struct {
+	Files []FileDto `json:"files"`
+}
+
+// InLocation renders the timestamps of the files in the given location.
+func (res *UnreferencedFilesResponse) InLocation(location *time.Location) {
+	for i := range res.Files {
+		res.Files[i].InLocation(location)
+	}
+}
@@ -0,0 +1,56 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"company-name/pkg/idgenerator"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// storedExtensionPattern matches the extensions kept on stored files, anything else is dropped.
+var storedExtensionPattern = regexp.MustCompile(`^\.[a-z0-9]{1,10}$`)
+
+type UploadFileRequest struct {
+	FileName string `json:"file_name" validate:"required"`
+	Data     []byte `json:"-"`
+	OwnerID  string `json:"-"`
+}
+
+// ToEntity builds the metadata of the uploaded file, whose path is only known once it is stored.
+func (req *UploadFileRequest) ToEntity() *entities.File {
+	file := &entities.File{
+		ID:           idgenerator.GenerateID(),
+		OriginalName: req.FileName,
+		MimeType:     http.DetectContentType(req.Data),
+		Size:         int64(len(req.Data)),
+		CreatedAt:    time.Now(),
+	}
+
+	if ownerID, err := idgenerator.ToPersistenceID(req.OwnerID); err == nil {
+		file.OwnerID = &ownerID
+	}
+	return file
+}
+
+// StoredName returns the name the uploaded file is stored under: its ID followed by the extension of the client's file
+// name, so that uploads never overwrite each other nor escape the storage directory.
+func (req *UploadFileRequest) StoredName(file *entities.File) string {
+	extension := strings.ToLower(filepath.Ext(req.FileName))
+	if !storedExtensionPattern.MatchString(extension) {
+		extension = ""
+	}
+	return file.ID.Hex() + extension
+}
+
+type UploadFileResponse struct {
+	FileDto
+}
+
+func UploadFileResponseFromEntity(entity *entities.File) *UploadFileResponse {
+	return &UploadFileResponse{
+		FileDto: *FileDtoFromEntity(entity),
+	}
+}
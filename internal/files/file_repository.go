@@ -0,0 +1,95 @@
+package files
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/database"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IFileRepository defines the interface for the metadata of stored files
+type IFileRepository interface {
+	Create(ctx context.Context, file *entities.File) error
+	FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]*entities.File, error)
+	FindByID(ctx context.Context, id string) (*entities.File, error)
+	FindAll(ctx context.Context) ([]*entities.File, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	AnonymizeByOwner(ctx context.Context, ownerID primitive.ObjectID) error
+}
+
+type Repository struct {
+	db database.IDatabase
+}
+
+// NewFileRepository initializes a new file metadata repository
+func NewFileRepository(db database.IDatabase) IFileRepository {
+	return &Repository{db: db}
+}
+
+// Create records the metadata of a stored file
+func (r *Repository) Create(ctx context.Context, file *entities.File) error {
+	if err := r.db.Create(ctx, constants.DbFilesCollection, file); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindByOwner retrieves the metadata of every file uploaded by the given user
+func (r *Repository) FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]*entities.File, error) {
+	files := []*entities.File{}
+	filter := bson.M{"owner_id": ownerID}
+
+	if err := r.db.Find(ctx, constants.DbFilesCollection, filter, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// FindByID retrieves the metadata of a stored file by its ID
+func (r *Repository) FindByID(ctx context.Context, id string) (*entities.File, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var file entities.File
+	if err := r.db.FindOne(ctx, constants.DbFilesCollection, bson.M{"_id": objectID}, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// FindAll retrieves the metadata of every stored file, most recent first
+func (r *Repository) FindAll(ctx context.Context) ([]*entities.File, error) {
+	files := []*entities.File{}
+
+	if err := r.db.FindWithPagination(ctx, constants.DbFilesCollection, bson.M{}, "created_at", constants.SortDesc, 0, 0, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Delete removes the metadata of a stored file
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return r.db.Delete(ctx, constants.DbFilesCollection, bson.M{"_id": id})
+}
+
+// AnonymizeByOwner anonymizes the metadata of every file uploaded by the given user, see entities.File.Anonymize
+func (r *Repository) AnonymizeByOwner(ctx context.Context, ownerID primitive.ObjectID) error {
+	files, err := r.FindByOwner(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		file.Anonymize()
+		update := bson.M{"$set": bson.M{"original_name": file.OriginalName}, "$unset": bson.M{"owner_id": ""}}
+		if err := r.db.Update(ctx, constants.DbFilesCollection, bson.M{"_id": file.ID}, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
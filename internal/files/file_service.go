@@ -0,0 +1,122 @@
+package files
+
+import (
+	"company-name/constants/msgkey"
+	"company-name/entities"
+	blocks "company-name/internal/content-blocks"
+	"company-name/internal/files/dtos"
+	"company-name/pkg/errors"
+	"company-name/pkg/file"
+	loc "company-name/pkg/localization"
+	"context"
+	"log"
+	"strconv"
+)
+
+type IFileService interface {
+	UploadFile(ctx context.Context, req *dtos.UploadFileRequest) (*dtos.UploadFileResponse, error)
+	DeleteFile(ctx context.Context, req *dtos.DeleteFileRequest) error
+	GetUnreferencedFiles(ctx context.Context) (*dtos.UnreferencedFilesResponse, error)
+}
+
+// AvatarFinder finds the users whose avatar is a stored file, implemented by the user repository.
+type AvatarFinder interface {
+	FindByAvatar(ctx context.Context, avatarURL string) ([]*entities.User, error)
+	FindAvatarURLs(ctx context.Context) (map[string]bool, error)
+}
+
+type Service struct {
+	repo    IFileRepository
+	storage *file.FileService
+	blocks  blocks.IContentBlockRepository
+	avatars AvatarFinder
+}
+
+// NewFileService initializes a new file service. The content blocks repository and the avatars of users tell which
+// files are in use, see ContentBlocks.FileIDs and User.AvatarURL.
+func NewFileService(repo IFileRepository, storage *file.FileService, blocks blocks.IContentBlockRepository, avatars AvatarFinder) IFileService {
+	return &Service{repo: repo, storage: storage, blocks: blocks, avatars: avatars}
+}
+
+// UploadFile stores the file under a name of its own and records its metadata, keeping the client's file name as its
+// original name and attributing it to the uploader when known.
+func (s *Service) UploadFile(ctx context.Context, req *dtos.UploadFileRequest) (*dtos.UploadFileResponse, error) {
+	uploaded := req.ToEntity()
+	path, err := s.storage.SaveFile(req.StoredName(uploaded), req.Data)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgFileResource), err)
+	}
+
+	uploaded.Path = path
+	if err := s.repo.Create(ctx, uploaded); err != nil {
+		if deleteErr := s.storage.DeleteFile(path); deleteErr != nil {
+			log.Printf("Error deleting file %s: %v", path, deleteErr)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgFileResource), err)
+	}
+
+	return dtos.UploadFileResponseFromEntity(uploaded), nil
+}
+
+// DeleteFile removes a stored file and its metadata, unless the content of a block still refers to it or a user has it
+// as avatar, in which case the blocks and users using it are reported.
+func (s *Service) DeleteFile(ctx context.Context, req *dtos.DeleteFileRequest) error {
+	stored, err := s.repo.FindByID(ctx, req.ID)
+	if err != nil {
+		return errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgFileResource), err)
+	}
+
+	usedBy, err := s.blocks.FindUsingFile(ctx, stored.ID)
+	if err != nil {
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+	avatarOf, err := s.avatars.FindByAvatar(ctx, stored.Path)
+	if err != nil {
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgUserResource), err)
+	}
+	if len(usedBy) > 0 || len(avatarOf) > 0 {
+		usages := make([]dtos.FileUsageDto, 0, len(usedBy)+len(avatarOf))
+		for _, contentBlock := range usedBy {
+			usages = append(usages, dtos.FileUsageDtoFromEntity(contentBlock))
+		}
+		for _, user := range avatarOf {
+			usages = append(usages, dtos.FileUsageDtoFromUser(user))
+		}
+		return errors.InUse(loc.L(msgkey.ErrFileInUse, strconv.Itoa(len(usages))), usages)
+	}
+
+	if err := s.repo.Delete(ctx, stored.ID); err != nil {
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceDeleted, msgkey.MsgFileResource), err)
+	}
+	// The metadata is gone, a file left behind in the storage is no longer reachable
+	if err := s.storage.DeleteFile(stored.Path); err != nil {
+		log.Printf("Error deleting file %s: %v", stored.Path, err)
+	}
+	return nil
+}
+
+// GetUnreferencedFiles lists the stored files that the content of no block refers to and that no user has as avatar,
+// most recent first. Files only used elsewhere, e.g. in the metadata of pages, are listed too.
+func (s *Service) GetUnreferencedFiles(ctx context.Context) (*dtos.UnreferencedFilesResponse, error) {
+	stored, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgFileResource), err)
+	}
+	referenced, err := s.blocks.FindReferencedFiles(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgContentBlockResource), err)
+	}
+
+	avatarURLs, err := s.avatars.FindAvatarURLs(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgUserResource), err)
+	}
+
+	response := &dtos.UnreferencedFilesResponse{Files: []dtos.FileDto{}}
+	for _, file := range stored {
+		if !referenced[file.ID] && !avatarURLs[file.Path] {
+			response.Files = append(response.Files, *dtos.FileDtoFromEntity(file))
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,60 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"time"
+)
+
+type InvitationDto struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	InvitedBy string    `json:"invited_by"`
+	Status    string    `json:"status"`
+	Expired   bool      `json:"expired"`
+	SendCount int       `json:"send_count"`
+	ExpiresAt time.Time `json:"expires_at"`
+	SentAt    time.Time `json:"sent_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func InvitationDtoFromEntity(entity *entities.Invitation) *InvitationDto {
+	return &InvitationDto{
+		ID:        entity.ID.Hex(),
+		UserID:    entity.UserID.Hex(),
+		Email:     entity.Email,
+		InvitedBy: entity.InvitedBy,
+		Status:    entity.Status,
+		Expired:   entity.IsPending() && entity.IsExpired(time.Now()),
+		SendCount: entity.SendCount,
+		ExpiresAt: entity.ExpiresAt,
+		SentAt:    entity.SentAt,
+		CreatedAt: entity.CreatedAt,
+	}
+}
+
+// InLocation renders the timestamps of the invitation in the given location.
+func (dto *InvitationDto) InLocation(location *time.Location) {
+	dto.ExpiresAt = dto.ExpiresAt.In(location)
+	dto.SentAt = dto.SentAt.In(location)
+	dto.CreatedAt = dto.CreatedAt.In(location)
+}
+
+type GetInvitationsResponse struct {
+	Invitations []InvitationDto `json:"invitations"`
+}
+
+func GetInvitationsResponseFromEntity(invitations []*entities.Invitation) *GetInvitationsResponse {
+	dtos := make([]InvitationDto, 0, len(invitations))
+	for _, invitation := range invitations {
+		dtos = append(dtos, *InvitationDtoFromEntity(invitation))
+	}
+	return &GetInvitationsResponse{Invitations: dtos}
+}
+
+// InLocation renders the timestamps of every invitation in the given location.
+func (res *GetInvitationsResponse) InLocation(location *time.Location) {
+	for i := range res.Invitations {
+		res.Invitations[i].InLocation(location)
+	}
+}
@@ -0,0 +1,62 @@
+package dtos
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/idgenerator"
+	"time"
+)
+
+type InviteUserRequest struct {
+	Email             string `json:"email" validate:"required,email"`
+	FirstName         string `json:"first_name" validate:"required"`
+	LastName          string `json:"last_name" validate:"required"`
+	PhoneNumber       string `json:"phone_number"`
+	Role              string `json:"role" validate:"omitempty,oneof=admin moderator user"`
+	PreferredLanguage string `json:"preferred_language" validate:"omitempty,language"`
+	InvitedBy         string `json:"-"`
+}
+
+// ToUserEntity returns the pending account of the invitee, which has no password until the invitation is accepted.
+func (req *InviteUserRequest) ToUserEntity() *entities.User {
+	role := req.Role
+	if role == "" {
+		role = constants.UserRoleUser
+	}
+
+	return &entities.User{
+		ID:                idgenerator.GenerateID(),
+		Email:             req.Email,
+		FirstName:         req.FirstName,
+		LastName:          req.LastName,
+		PhoneNumber:       req.PhoneNumber,
+		PreferredLanguage: req.PreferredLanguage,
+		Role:              role,
+		Status:            constants.UserStatusPending,
+		Version:           1,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+}
+
+func (req *InviteUserRequest) ToEntity(user *entities.User) *entities.Invitation {
+	return &entities.Invitation{
+		ID:        idgenerator.GenerateID(),
+		UserID:    user.ID,
+		Email:     user.Email,
+		InvitedBy: req.InvitedBy,
+		Status:    constants.InvitationStatusPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// InvitationRequest identifies the invitation to resend or revoke.
+type InvitationRequest struct {
+	ID string `json:"-" validate:"required"`
+}
+
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
@@ -0,0 +1,83 @@
+package invitation
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/database"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// IInvitationRepository defines the interface for the invitations sent to users
+type IInvitationRepository interface {
+	Create(ctx context.Context, invitation *entities.Invitation) error
+	UpdatePending(ctx context.Context, invitation *entities.Invitation) error
+	FindByID(ctx context.Context, id string) (*entities.Invitation, error)
+	FindPending(ctx context.Context) ([]*entities.Invitation, error)
+	WithTransaction(ctx context.Context, function func(ctx context.Context) error) error
+}
+
+type Repository struct {
+	db database.IDatabase
+}
+
+// NewInvitationRepository initializes a new invitation repository
+func NewInvitationRepository(db database.IDatabase) IInvitationRepository {
+	return &Repository{db: db}
+}
+
+// Create records a new invitation
+func (r *Repository) Create(ctx context.Context, invitation *entities.Invitation) error {
+	if err := r.db.Create(ctx, constants.DbInvitationsCollection, invitation); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdatePending saves an invitation provided it is still pending, so that an invitation accepted or revoked meanwhile
+// is left untouched
+func (r *Repository) UpdatePending(ctx context.Context, invitation *entities.Invitation) error {
+	filter := bson.M{"_id": invitation.ID, "status": constants.InvitationStatusPending}
+	update := bson.M{"$set": invitation}
+
+	if err := r.db.Update(ctx, constants.DbInvitationsCollection, filter, update); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindByID retrieves an invitation by its ID
+func (r *Repository) FindByID(ctx context.Context, id string) (*entities.Invitation, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var invitation entities.Invitation
+	if err := r.db.FindOne(ctx, constants.DbInvitationsCollection, bson.M{"_id": objectID}, &invitation); err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindPending retrieves the invitations neither accepted nor revoked, expired ones included, most recent first
+func (r *Repository) FindPending(ctx context.Context) ([]*entities.Invitation, error) {
+	invitations := []*entities.Invitation{}
+	filter := bson.M{"status": constants.InvitationStatusPending}
+
+	if err := r.db.FindWithPagination(ctx, constants.DbInvitationsCollection, filter, "created_at", constants.SortDesc, 0, 0, &invitations); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// WithTransaction runs function in a transaction. The operations made with the context it is given, through this
+// repository or any other sharing the database, are committed together or not at all
+func (r *Repository) WithTransaction(ctx context.Context, function func(ctx context.Context) error) error {
+	return r.db.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		return function(sessCtx)
+	})
+}
@@ -0,0 +1,261 @@
+package invitation
+
+import (
+	"company-name/configs"
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/entities"
+	"company-name/internal/invitation/dtos"
+	"company-name/internal/user"
+	"company-name/pkg/database"
+	"company-name/pkg/email"
+	"company-name/pkg/errors"
+	"company-name/pkg/hasher"
+	"company-name/pkg/idgenerator"
+	"company-name/pkg/jwttoken"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/validators"
+	"context"
+	goerrors "errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// IInvitationService defines the invitation flow: administrators invite users, who accept by choosing a password.
+type IInvitationService interface {
+	InviteUser(ctx context.Context, req *dtos.InviteUserRequest) (*dtos.InvitationDto, error)
+	GetPendingInvitations(ctx context.Context) (*dtos.GetInvitationsResponse, error)
+	ResendInvitation(ctx context.Context, req *dtos.InvitationRequest) (*dtos.InvitationDto, error)
+	RevokeInvitation(ctx context.Context, req *dtos.InvitationRequest) error
+	AcceptInvitation(ctx context.Context, req *dtos.AcceptInvitationRequest) error
+}
+
+type Service struct {
+	repo         IInvitationRepository
+	users        user.IUserRepository
+	validator    validators.IValidator
+	emailService email.IEmailService
+	config       *configs.Config
+}
+
+// NewInvitationService initializes a new invitation service
+func NewInvitationService(
+	repo IInvitationRepository,
+	users user.IUserRepository,
+	validator validators.IValidator,
+	emailService email.IEmailService,
+	config *configs.Config,
+) IInvitationService {
+	return &Service{
+		repo:         repo,
+		users:        users,
+		validator:    validator,
+		emailService: emailService,
+		config:       config,
+	}
+}
+
+// InviteUser creates a pending account without password for the invitee and emails them a link to set it.
+func (s *Service) InviteUser(ctx context.Context, req *dtos.InviteUserRequest) (*dtos.InvitationDto, error) {
+	if _, err := s.users.FindByEmail(ctx, req.Email); err == nil {
+		return nil, errors.ConflictM(msgkey.ErrEmailAlreadyUsed, nil)
+	}
+
+	invitee := req.ToUserEntity()
+	if err := s.users.Create(ctx, invitee); err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, errors.ConflictM(msgkey.ErrEmailAlreadyUsed, err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgUserResource), err)
+	}
+
+	invitation := req.ToEntity(invitee)
+	s.renew(invitation)
+	if err := s.repo.Create(ctx, invitation); err != nil {
+		// Roll back the account so that the email can be invited again
+		if deleteErr := s.users.Delete(ctx, invitee); deleteErr != nil {
+			log.Printf("Error deleting user %s after failing to record its invitation: %v", invitee.ID.Hex(), deleteErr)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgInvitationResource), err)
+	}
+
+	s.sendInvitationEmail(invitee, invitation)
+
+	return dtos.InvitationDtoFromEntity(invitation), nil
+}
+
+// GetPendingInvitations returns the invitations neither accepted nor revoked, most recent first.
+func (s *Service) GetPendingInvitations(ctx context.Context) (*dtos.GetInvitationsResponse, error) {
+	invitations, err := s.repo.FindPending(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgInvitationResource), err)
+	}
+
+	return dtos.GetInvitationsResponseFromEntity(invitations), nil
+}
+
+// ResendInvitation emails a new link with a renewed expiry. Links sent previously stop working.
+func (s *Service) ResendInvitation(ctx context.Context, req *dtos.InvitationRequest) (*dtos.InvitationDto, error) {
+	invitation, err := s.findPending(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitee, err := s.users.FindByID(ctx, invitation.UserID.Hex())
+	if err != nil {
+		return nil, errors.NotFound(err)
+	}
+
+	s.renew(invitation)
+	if err := s.repo.UpdatePending(ctx, invitation); err != nil {
+		if database.IsNotFound(err) {
+			return nil, errors.ConflictM(msgkey.ErrInvitationNotPending, err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgInvitationResource), err)
+	}
+
+	s.sendInvitationEmail(invitee, invitation)
+
+	return dtos.InvitationDtoFromEntity(invitation), nil
+}
+
+// RevokeInvitation invalidates the link of an invitation and removes the account created for the invitee, which was
+// never used since it has no password.
+func (s *Service) RevokeInvitation(ctx context.Context, req *dtos.InvitationRequest) error {
+	invitation, err := s.findPending(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	invitation.Status = constants.InvitationStatusRevoked
+	invitation.RevokedAt = &now
+	invitation.UpdatedAt = now
+	if err := s.repo.UpdatePending(ctx, invitation); err != nil {
+		if database.IsNotFound(err) {
+			return errors.ConflictM(msgkey.ErrInvitationNotPending, err)
+		}
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgInvitationResource), err)
+	}
+
+	invitee, err := s.users.FindByID(ctx, invitation.UserID.Hex())
+	if err == nil && invitee.HashedPassword == "" && invitee.CurrentStatus() == constants.UserStatusPending {
+		if err := s.users.Delete(ctx, invitee); err != nil {
+			log.Printf("Error deleting user %s of revoked invitation %s: %v", invitee.ID.Hex(), invitation.ID.Hex(), err)
+		}
+	}
+
+	return nil
+}
+
+// AcceptInvitation sets the password chosen by the invitee and activates their account, marking the invitation accepted
+// in the same transaction.
+func (s *Service) AcceptInvitation(ctx context.Context, req *dtos.AcceptInvitationRequest) error {
+	claims, err := jwttoken.ValidateTypedToken(req.Token, constants.InvitationTokenType)
+	if err != nil {
+		if goerrors.Is(err, jwt.ErrTokenExpired) {
+			return errors.BadRequestM(msgkey.ErrInvitationExpired, err)
+		}
+		return errors.BadRequestM(msgkey.ErrInvalidInvitationToken, err)
+	}
+
+	// The link carries no tenant, the invitation is looked up across tenants and the rest of the work is scoped to its own
+	invitationID, _ := claims["sub"].(string)
+	invitation, err := s.repo.FindByID(database.WithoutTenantScope(ctx), invitationID)
+	if err != nil {
+		return errors.BadRequestM(msgkey.ErrInvalidInvitationToken, err)
+	}
+	if invitation.TenantID != nil {
+		ctx = database.WithTenant(ctx, *invitation.TenantID)
+	}
+
+	// Resending replaces the token id, so only the latest link is accepted
+	if tokenID, _ := claims["jti"].(string); tokenID != invitation.TokenID {
+		return errors.BadRequestM(msgkey.ErrInvalidInvitationToken, nil)
+	}
+	if !invitation.IsPending() {
+		return errors.ConflictM(msgkey.ErrInvitationNotPending, nil)
+	}
+	if invitation.IsExpired(time.Now()) {
+		return errors.BadRequestM(msgkey.ErrInvitationExpired, nil)
+	}
+
+	invitee, err := s.users.FindByID(ctx, invitation.UserID.Hex())
+	if err != nil {
+		return errors.BadRequestM(msgkey.ErrInvalidInvitationToken, err)
+	}
+
+	hashedPassword, err := hasher.HashPassword(req.Password)
+	if err != nil {
+		return errors.InternalServerError(err)
+	}
+
+	invitee.HashedPassword = hashedPassword
+	invitee.Status = constants.UserStatusActivated
+
+	now := time.Now()
+	invitation.Status = constants.InvitationStatusAccepted
+	invitation.AcceptedAt = &now
+	invitation.UpdatedAt = now
+
+	// The invitation is claimed first, so that a link accepted twice concurrently activates the user once
+	err = s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdatePending(ctx, invitation); err != nil {
+			if database.IsNotFound(err) {
+				return errors.ConflictM(msgkey.ErrInvitationNotPending, err)
+			}
+			return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgInvitationResource), err)
+		}
+		if err := s.users.Update(ctx, invitee); err != nil {
+			return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgUserResource), err)
+		}
+		return nil
+	})
+	if err != nil {
+		var httpError errors.HttpError
+		if goerrors.As(err, &httpError) {
+			return err
+		}
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgInvitationResource), err)
+	}
+
+	return nil
+}
+
+// findPending loads an invitation that can still be resent or revoked.
+func (s *Service) findPending(ctx context.Context, id string) (*entities.Invitation, error) {
+	invitation, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgInvitationResource), err)
+	}
+
+	if !invitation.IsPending() {
+		return nil, errors.ConflictM(msgkey.ErrInvitationNotPending, nil)
+	}
+	return invitation, nil
+}
+
+// renew gives the invitation a new token id and expiry, invalidating the links sent before.
+func (s *Service) renew(invitation *entities.Invitation) {
+	now := time.Now()
+	invitation.TokenID = idgenerator.GenerateID().Hex()
+	invitation.ExpiresAt = now.Add(time.Duration(s.config.JWT.InvitationExpiration) * time.Millisecond)
+	invitation.SentAt = now
+	invitation.SendCount++
+	invitation.UpdatedAt = now
+}
+
+func (s *Service) sendInvitationEmail(invitee *entities.User, invitation *entities.Invitation) {
+	token := jwttoken.GenerateTypedToken(invitation.ID.Hex(), constants.InvitationTokenType, invitation.TokenID, invitation.ExpiresAt)
+	link := fmt.Sprintf("%s?token=%s", s.config.App.InvitationUrl, token)
+	expiresAt := invitation.ExpiresAt.UTC().Format(time.RFC1123)
+
+	go func() {
+		if err := s.emailService.SendInvitationEmail(invitee.Email, invitee.FullName(), link, expiresAt, invitee.PreferredLanguage); err != nil {
+			log.Printf("Error sending invitation email to user %s: %v", invitee.ID.Hex(), err)
+		}
+	}()
+}
@@ -0,0 +1,45 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"time"
+)
+
+type SetMemberRequest struct {
+	UserID string `json:"-" validate:"required"`
+	Role   string `json:"role" validate:"required,oneof=admin moderator user"`
+}
+
+type MemberRequest struct {
+	UserID string `json:"-" validate:"required"`
+}
+
+type MembershipDto struct {
+	OrganizationID string    `json:"organization_id"`
+	UserID         string    `json:"user_id"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func MembershipDtoFromEntity(entity *entities.Membership) *MembershipDto {
+	return &MembershipDto{
+		OrganizationID: entity.OrganizationID.Hex(),
+		UserID:         entity.UserID.Hex(),
+		Role:           entity.Role,
+		CreatedAt:      entity.CreatedAt,
+		UpdatedAt:      entity.UpdatedAt,
+	}
+}
+
+type GetMembersResponse struct {
+	Members []MembershipDto `json:"members"`
+}
+
+func GetMembersResponseFromEntity(memberships []*entities.Membership) *GetMembersResponse {
+	dtos := make([]MembershipDto, 0, len(memberships))
+	for _, membership := range memberships {
+		dtos = append(dtos, *MembershipDtoFromEntity(membership))
+	}
+	return &GetMembersResponse{Members: dtos}
+}
@@ -0,0 +1,71 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"company-name/pkg/idgenerator"
+	"company-name/pkg/utils/slug"
+	"time"
+)
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Slug string `json:"slug" validate:"omitempty,max=100"`
+}
+
+// ToEntity returns the organization, whose slug is derived from the name unless given.
+func (req *CreateOrganizationRequest) ToEntity() *entities.Organization {
+	organizationSlug := req.Slug
+	if organizationSlug == "" {
+		organizationSlug = req.Name
+	}
+
+	return &entities.Organization{
+		ID:        idgenerator.GenerateID(),
+		Name:      req.Name,
+		Slug:      slug.GenerateSlug(organizationSlug),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+type OrganizationDto struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func OrganizationDtoFromEntity(entity *entities.Organization) *OrganizationDto {
+	return &OrganizationDto{
+		ID:        entity.ID.Hex(),
+		Name:      entity.Name,
+		Slug:      entity.Slug,
+		CreatedAt: entity.CreatedAt,
+	}
+}
+
+type GetOrganizationsResponse struct {
+	Organizations []OrganizationDto `json:"organizations"`
+}
+
+func GetOrganizationsResponseFromEntity(organizations []*entities.Organization) *GetOrganizationsResponse {
+	dtos := make([]OrganizationDto, 0, len(organizations))
+	for _, organization := range organizations {
+		dtos = append(dtos, *OrganizationDtoFromEntity(organization))
+	}
+	return &GetOrganizationsResponse{Organizations: dtos}
+}
+
+type GetMyOrganizationsRequest struct {
+	UserID string `json:"-" validate:"required"`
+}
+
+// MyOrganizationDto is an organization the user can act in, with the role they hold there.
+type MyOrganizationDto struct {
+	OrganizationDto
+	Role string `json:"role"`
+}
+
+type GetMyOrganizationsResponse struct {
+	Organizations []MyOrganizationDto `json:"organizations"`
+}
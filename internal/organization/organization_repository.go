@@ -0,0 +1,142 @@
+package organization
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/database"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IOrganizationRepository defines the interface for organizations and the memberships of their users
+type IOrganizationRepository interface {
+	Create(ctx context.Context, organization *entities.Organization) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entities.Organization, error)
+	FindBySlug(ctx context.Context, slug string) (*entities.Organization, error)
+	FindAll(ctx context.Context) ([]*entities.Organization, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*entities.Organization, error)
+	CreateMembership(ctx context.Context, membership *entities.Membership) error
+	UpdateMembership(ctx context.Context, membership *entities.Membership) error
+	DeleteMembership(ctx context.Context, organizationID, userID primitive.ObjectID) error
+	FindMembership(ctx context.Context, organizationID, userID primitive.ObjectID) (*entities.Membership, error)
+	FindMemberships(ctx context.Context, organizationID primitive.ObjectID) ([]*entities.Membership, error)
+	FindUserMemberships(ctx context.Context, userID primitive.ObjectID) ([]*entities.Membership, error)
+}
+
+type Repository struct {
+	db database.IDatabase
+}
+
+// NewOrganizationRepository initializes a new organization repository
+func NewOrganizationRepository(db database.IDatabase) IOrganizationRepository {
+	return &Repository{db: db}
+}
+
+// Create adds a new organization
+func (r *Repository) Create(ctx context.Context, organization *entities.Organization) error {
+	if err := r.db.Create(ctx, constants.DbOrganizationsCollection, organization); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindByID retrieves an organization by its ID
+func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*entities.Organization, error) {
+	var organization entities.Organization
+	if err := r.db.FindOne(ctx, constants.DbOrganizationsCollection, bson.M{"_id": id}, &organization); err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+// FindBySlug retrieves an organization by its slug
+func (r *Repository) FindBySlug(ctx context.Context, slug string) (*entities.Organization, error) {
+	var organization entities.Organization
+	if err := r.db.FindOne(ctx, constants.DbOrganizationsCollection, bson.M{"slug": slug}, &organization); err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+// FindAll retrieves every organization, by name
+func (r *Repository) FindAll(ctx context.Context) ([]*entities.Organization, error) {
+	organizations := []*entities.Organization{}
+	if err := r.db.FindWithPagination(ctx, constants.DbOrganizationsCollection, bson.M{}, "name", constants.SortAsc, 0, 0, &organizations); err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
+
+// FindByIDs retrieves the organizations with the given IDs
+func (r *Repository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*entities.Organization, error) {
+	organizations := []*entities.Organization{}
+	filter := bson.M{"_id": bson.M{"$in": ids}}
+
+	if err := r.db.Find(ctx, constants.DbOrganizationsCollection, filter, &organizations); err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
+
+// CreateMembership grants a user a role in an organization
+func (r *Repository) CreateMembership(ctx context.Context, membership *entities.Membership) error {
+	if err := r.db.Create(ctx, constants.DbMembershipsCollection, membership); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateMembership persists the role of an existing membership
+func (r *Repository) UpdateMembership(ctx context.Context, membership *entities.Membership) error {
+	filter := bson.M{"_id": membership.ID}
+	update := bson.M{"$set": bson.M{"role": membership.Role, "updated_at": membership.UpdatedAt}}
+
+	if err := r.db.Update(ctx, constants.DbMembershipsCollection, filter, update); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteMembership removes a user from an organization
+func (r *Repository) DeleteMembership(ctx context.Context, organizationID, userID primitive.ObjectID) error {
+	filter := bson.M{"organization_id": organizationID, "user_id": userID}
+	if err := r.db.Delete(ctx, constants.DbMembershipsCollection, filter); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindMembership retrieves the membership of a user in an organization
+func (r *Repository) FindMembership(ctx context.Context, organizationID, userID primitive.ObjectID) (*entities.Membership, error) {
+	var membership entities.Membership
+	filter := bson.M{"organization_id": organizationID, "user_id": userID}
+
+	if err := r.db.FindOne(ctx, constants.DbMembershipsCollection, filter, &membership); err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// FindMemberships retrieves the memberships of an organization, oldest first
+func (r *Repository) FindMemberships(ctx context.Context, organizationID primitive.ObjectID) ([]*entities.Membership, error) {
+	memberships := []*entities.Membership{}
+	filter := bson.M{"organization_id": organizationID}
+
+	if err := r.db.FindWithPagination(ctx, constants.DbMembershipsCollection, filter, "created_at", constants.SortAsc, 0, 0, &memberships); err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
+
+// FindUserMemberships retrieves the memberships of a user across organizations
+func (r *Repository) FindUserMemberships(ctx context.Context, userID primitive.ObjectID) ([]*entities.Membership, error) {
+	memberships := []*entities.Membership{}
+	filter := bson.M{"user_id": userID}
+
+	if err := r.db.Find(ctx, constants.DbMembershipsCollection, filter, &memberships); err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
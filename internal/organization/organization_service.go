@@ -0,0 +1,231 @@
+package organization
+
+import (
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/entities"
+	"company-name/internal/organization/dtos"
+	"company-name/internal/user"
+	"company-name/pkg/database"
+	"company-name/pkg/errors"
+	"company-name/pkg/idgenerator"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/validators"
+	"context"
+	goerrors "errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errNotMember = goerrors.New("user is not a member of the organization")
+
+// IOrganizationService manages the organizations served by the platform, which are the tenants of its data, and the
+// roles users hold in them.
+type IOrganizationService interface {
+	CreateOrganization(ctx context.Context, req *dtos.CreateOrganizationRequest) (*dtos.OrganizationDto, error)
+	GetOrganizations(ctx context.Context) (*dtos.GetOrganizationsResponse, error)
+	GetMyOrganizations(ctx context.Context, req *dtos.GetMyOrganizationsRequest) (*dtos.GetMyOrganizationsResponse, error)
+	GetMembers(ctx context.Context) (*dtos.GetMembersResponse, error)
+	SetMember(ctx context.Context, req *dtos.SetMemberRequest) (*dtos.MembershipDto, error)
+	RemoveMember(ctx context.Context, req *dtos.MemberRequest) error
+	ResolveTenant(ctx context.Context, reference string) (*entities.Organization, error)
+	RoleIn(ctx context.Context, organizationID primitive.ObjectID, user *entities.User) (string, error)
+}
+
+type Service struct {
+	repo      IOrganizationRepository
+	users     user.IUserRepository
+	validator validators.IValidator
+}
+
+// NewOrganizationService initializes a new organization service
+func NewOrganizationService(repo IOrganizationRepository, users user.IUserRepository, validator validators.IValidator) IOrganizationService {
+	return &Service{
+		repo:      repo,
+		users:     users,
+		validator: validator,
+	}
+}
+
+// CreateOrganization registers a new tenant.
+func (s *Service) CreateOrganization(ctx context.Context, req *dtos.CreateOrganizationRequest) (*dtos.OrganizationDto, error) {
+	organization := req.ToEntity()
+	if err := s.validator.ValidateStruct(organization); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, organization); err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, errors.ConflictM(msgkey.ErrSlugAlreadyUsed, err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgOrganizationResource), err)
+	}
+
+	return dtos.OrganizationDtoFromEntity(organization), nil
+}
+
+// GetOrganizations returns every organization, by name.
+func (s *Service) GetOrganizations(ctx context.Context) (*dtos.GetOrganizationsResponse, error) {
+	organizations, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgOrganizationResource), err)
+	}
+
+	return dtos.GetOrganizationsResponseFromEntity(organizations), nil
+}
+
+// GetMyOrganizations returns the organizations the user can act in: the one they belong to and the ones they were
+// made a member of, with their role in each.
+func (s *Service) GetMyOrganizations(ctx context.Context, req *dtos.GetMyOrganizationsRequest) (*dtos.GetMyOrganizationsResponse, error) {
+	member, err := s.users.FindByID(database.WithoutTenantScope(ctx), req.UserID)
+	if err != nil {
+		return nil, errors.NotFound(err)
+	}
+
+	memberships, err := s.repo.FindUserMemberships(ctx, member.ID)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgMembershipResource), err)
+	}
+
+	roles := make(map[primitive.ObjectID]string, len(memberships)+1)
+	ids := make([]primitive.ObjectID, 0, len(memberships)+1)
+	if member.TenantID != nil {
+		roles[*member.TenantID] = member.Role
+		ids = append(ids, *member.TenantID)
+	}
+	for _, membership := range memberships {
+		if _, ok := roles[membership.OrganizationID]; !ok {
+			ids = append(ids, membership.OrganizationID)
+		}
+		roles[membership.OrganizationID] = membership.Role
+	}
+
+	organizations, err := s.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgOrganizationResource), err)
+	}
+
+	response := &dtos.GetMyOrganizationsResponse{Organizations: make([]dtos.MyOrganizationDto, 0, len(organizations))}
+	for _, organization := range organizations {
+		response.Organizations = append(response.Organizations, dtos.MyOrganizationDto{
+			OrganizationDto: *dtos.OrganizationDtoFromEntity(organization),
+			Role:            roles[organization.ID],
+		})
+	}
+	return response, nil
+}
+
+// GetMembers returns the memberships of the organization the request is scoped to.
+func (s *Service) GetMembers(ctx context.Context) (*dtos.GetMembersResponse, error) {
+	organizationID, err := s.currentTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.repo.FindMemberships(ctx, organizationID)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgMembershipResource), err)
+	}
+
+	return dtos.GetMembersResponseFromEntity(memberships), nil
+}
+
+// SetMember grants a user a role in the organization the request is scoped to, or changes the role they hold there.
+// The user may belong to another organization or to the platform itself.
+func (s *Service) SetMember(ctx context.Context, req *dtos.SetMemberRequest) (*dtos.MembershipDto, error) {
+	organizationID, err := s.currentTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.users.FindByID(database.WithoutTenantScope(ctx), req.UserID)
+	if err != nil {
+		return nil, errors.NotFound(err)
+	}
+
+	now := time.Now()
+	membership, err := s.repo.FindMembership(ctx, organizationID, member.ID)
+	switch {
+	case err == nil:
+		membership.Role = req.Role
+		membership.UpdatedAt = now
+		err = s.repo.UpdateMembership(ctx, membership)
+	case database.IsNotFound(err):
+		membership = &entities.Membership{
+			ID:             idgenerator.GenerateID(),
+			OrganizationID: organizationID,
+			UserID:         member.ID,
+			Role:           req.Role,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		err = s.repo.CreateMembership(ctx, membership)
+	}
+	if err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, errors.Conflict(err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgMembershipResource), err)
+	}
+
+	return dtos.MembershipDtoFromEntity(membership), nil
+}
+
+// RemoveMember revokes the role a user was granted in the organization the request is scoped to.
+func (s *Service) RemoveMember(ctx context.Context, req *dtos.MemberRequest) error {
+	organizationID, err := s.currentTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	userID, err := primitive.ObjectIDFromHex(req.UserID)
+	if err != nil {
+		return errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgMembershipResource), err)
+	}
+
+	if err := s.repo.DeleteMembership(ctx, organizationID, userID); err != nil {
+		if database.IsNotFound(err) {
+			return errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgMembershipResource), err)
+		}
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceDeleted, msgkey.MsgMembershipResource), err)
+	}
+
+	return nil
+}
+
+// ResolveTenant finds the organization a request names, by ID or by slug.
+func (s *Service) ResolveTenant(ctx context.Context, reference string) (*entities.Organization, error) {
+	if id, err := primitive.ObjectIDFromHex(reference); err == nil {
+		return s.repo.FindByID(ctx, id)
+	}
+	return s.repo.FindBySlug(ctx, reference)
+}
+
+// RoleIn returns the role a user holds in an organization: the role of their membership, otherwise their own role
+// when they belong to it. Platform administrators act as administrators of every organization.
+func (s *Service) RoleIn(ctx context.Context, organizationID primitive.ObjectID, user *entities.User) (string, error) {
+	membership, err := s.repo.FindMembership(ctx, organizationID, user.ID)
+	if err == nil {
+		return membership.Role, nil
+	}
+	if !database.IsNotFound(err) {
+		return "", err
+	}
+
+	switch {
+	case user.TenantID != nil && *user.TenantID == organizationID:
+		return user.Role, nil
+	case user.TenantID == nil && user.Role == constants.UserRoleAdmin:
+		return constants.UserRoleAdmin, nil
+	}
+	return "", errNotMember
+}
+
+func (s *Service) currentTenant(ctx context.Context) (primitive.ObjectID, error) {
+	organizationID, ok := database.TenantFromContext(ctx)
+	if !ok {
+		return primitive.NilObjectID, errors.BadRequestM(msgkey.ErrTenantRequired, nil)
+	}
+	return organizationID, nil
+}
@@ -0,0 +1,125 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"company-name/pkg/idgenerator"
+	"company-name/pkg/utils/slug"
+	"time"
+)
+
+type CreatePageRequest struct {
+	Title           string   `json:"title" validate:"required,max=200"`
+	Slug            string   `json:"slug" validate:"omitempty,max=100"` // Derived from the title when omitted
+	MetaDescription string   `json:"meta_description" validate:"max=300"`
+	OGImage         string   `json:"og_image" validate:"omitempty,url"`
+	Sections        []string `json:"sections" validate:"unique,dive,required"`
+	Tags            []string `json:"tags" validate:"dive,required,max=50"` // Repeated tags are merged, whatever their case
+}
+
+// ToEntity returns the page, whose slug is derived from the title unless given.
+func (req *CreatePageRequest) ToEntity() *entities.Page {
+	pageSlug := req.Slug
+	if pageSlug == "" {
+		pageSlug = req.Title
+	}
+
+	sections := req.Sections
+	if sections == nil {
+		sections = []string{}
+	}
+
+	return &entities.Page{
+		ID:              idgenerator.GenerateID(),
+		Slug:            slug.GenerateSlug(pageSlug),
+		Title:           req.Title,
+		MetaDescription: req.MetaDescription,
+		OGImage:         req.OGImage,
+		Sections:        sections,
+		Tags:            entities.NormalizeTags(req.Tags),
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+}
+
+// UpdatePageRequest replaces the metadata of a page. Its slug cannot change, since its blocks refer to it.
+type UpdatePageRequest struct {
+	Slug            string    `json:"-" validate:"required"`
+	Title           string    `json:"title" validate:"required,max=200"`
+	MetaDescription string    `json:"meta_description" validate:"max=300"`
+	OGImage         string    `json:"og_image" validate:"omitempty,url"`
+	Sections        *[]string `json:"sections" validate:"omitempty,unique,dive,required"` // Unchanged when omitted
+	Tags            *[]string `json:"tags" validate:"omitempty,dive,required,max=50"`     // Unchanged when omitted
+}
+
+// ApplyTo copies the updated metadata, and the sections and tags when given, onto the stored page.
+func (req *UpdatePageRequest) ApplyTo(page *entities.Page) {
+	page.Title = req.Title
+	page.MetaDescription = req.MetaDescription
+	page.OGImage = req.OGImage
+	if req.Sections != nil {
+		page.Sections = *req.Sections
+	}
+	if req.Tags != nil {
+		page.Tags = entities.NormalizeTags(*req.Tags)
+	}
+	page.UpdatedAt = time.Now()
+}
+
+// ReorderSectionsRequest gives the sections of a page a new order. It must list the same sections as the page.
+type ReorderSectionsRequest struct {
+	Slug     string   `json:"-" validate:"required"`
+	Sections []string `json:"sections" validate:"required,unique,dive,required"`
+}
+
+type PageRequest struct {
+	Slug string `json:"-" validate:"required"`
+}
+
+type PageDto struct {
+	Slug            string    `json:"slug"`
+	Title           string    `json:"title"`
+	MetaDescription string    `json:"meta_description"`
+	OGImage         string    `json:"og_image"`
+	Sections        []string  `json:"sections"`
+	Tags            []string  `json:"tags"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func PageDtoFromEntity(entity *entities.Page) *PageDto {
+	return &PageDto{
+		Slug:            entity.Slug,
+		Title:           entity.Title,
+		MetaDescription: entity.MetaDescription,
+		OGImage:         entity.OGImage,
+		Sections:        entity.Sections,
+		Tags:            entities.NormalizeTags(entity.Tags),
+		CreatedAt:       entity.CreatedAt,
+		UpdatedAt:       entity.UpdatedAt,
+	}
+}
+
+// InLocation renders the timestamps of the page in the given location.
+func (dto *PageDto) InLocation(location *time.Location) {
+	dto.CreatedAt = dto.CreatedAt.In(location)
+	dto.UpdatedAt = dto.UpdatedAt.In(location)
+}
+
+type GetPagesResponse struct {
+	Pages []PageDto `json:"pages"`
+}
+
+func GetPagesResponseFromEntity(pages []*entities.Page) *GetPagesResponse {
+	dtos := make([]PageDto, 0, len(pages))
+	for _, page := range pages {
+		dtos = append(dtos, *PageDtoFromEntity(page))
+	}
+	return &GetPagesResponse{Pages: dtos}
+}
+
+// InLocation renders the timestamps of the pages in the given location.
+func (dto *GetPagesResponse) InLocation(location *time.Location) {
+	for i := range dto.Pages {
+		dto.Pages[i].InLocation(location)
+	}
+}
@@ -0,0 +1,81 @@
+package page
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/database"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// IPageRepository defines the interface for the metadata of pages
+type IPageRepository interface {
+	Create(ctx context.Context, page *entities.Page) error
+	Update(ctx context.Context, page *entities.Page) error
+	Delete(ctx context.Context, slug string) error
+	FindBySlug(ctx context.Context, slug string) (*entities.Page, error)
+	FindAll(ctx context.Context) ([]*entities.Page, error)
+	FindByTag(ctx context.Context, tag string) ([]*entities.Page, error)
+}
+
+type Repository struct {
+	db database.IDatabase
+}
+
+// NewPageRepository initializes a new page repository
+func NewPageRepository(db database.IDatabase) IPageRepository {
+	return &Repository{db: db}
+}
+
+// Create adds a new page
+func (r *Repository) Create(ctx context.Context, page *entities.Page) error {
+	return r.db.Create(ctx, constants.DbPagesCollection, page)
+}
+
+// Update persists the metadata and sections of an existing page
+func (r *Repository) Update(ctx context.Context, page *entities.Page) error {
+	filter := bson.M{"_id": page.ID}
+	update := bson.M{"$set": bson.M{
+		"title":            page.Title,
+		"meta_description": page.MetaDescription,
+		"og_image":         page.OGImage,
+		"sections":         page.Sections,
+		"tags":             page.Tags,
+		"updated_at":       page.UpdatedAt,
+	}}
+
+	return r.db.Update(ctx, constants.DbPagesCollection, filter, update)
+}
+
+// Delete removes a page by slug
+func (r *Repository) Delete(ctx context.Context, slug string) error {
+	return r.db.Delete(ctx, constants.DbPagesCollection, bson.M{"slug": slug})
+}
+
+// FindBySlug retrieves a page by its slug
+func (r *Repository) FindBySlug(ctx context.Context, slug string) (*entities.Page, error) {
+	var page entities.Page
+	if err := r.db.FindOne(ctx, constants.DbPagesCollection, bson.M{"slug": slug}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// FindAll retrieves every page, by slug
+func (r *Repository) FindAll(ctx context.Context) ([]*entities.Page, error) {
+	pages := []*entities.Page{}
+	if err := r.db.FindWithPagination(ctx, constants.DbPagesCollection, bson.M{}, "slug", constants.SortAsc, 0, 0, &pages); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// FindByTag retrieves the pages carrying a tag, by slug
+func (r *Repository) FindByTag(ctx context.Context, tag string) ([]*entities.Page, error) {
+	pages := []*entities.Page{}
+	if err := r.db.FindWithPagination(ctx, constants.DbPagesCollection, bson.M{"tags": tag}, "slug", constants.SortAsc, 0, 0, &pages); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
@@ -0,0 +1,139 @@
+package page
+
+import (
+	"company-name/constants/msgkey"
+	"company-name/entities"
+	"company-name/internal/page/dtos"
+	"company-name/pkg/database"
+	"company-name/pkg/errors"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/validators"
+	"context"
+	"slices"
+	"time"
+)
+
+// IPageService manages the metadata of pages and the order of their sections.
+type IPageService interface {
+	CreatePage(ctx context.Context, req *dtos.CreatePageRequest) (*dtos.PageDto, error)
+	UpdatePage(ctx context.Context, req *dtos.UpdatePageRequest) (*dtos.PageDto, error)
+	ReorderSections(ctx context.Context, req *dtos.ReorderSectionsRequest) (*dtos.PageDto, error)
+	DeletePage(ctx context.Context, req *dtos.PageRequest) error
+	GetPage(ctx context.Context, req *dtos.PageRequest) (*dtos.PageDto, error)
+	GetPages(ctx context.Context) (*dtos.GetPagesResponse, error)
+}
+
+type Service struct {
+	repo      IPageRepository
+	validator validators.IValidator
+}
+
+// NewPageService initializes a new page service
+func NewPageService(repo IPageRepository, validator validators.IValidator) IPageService {
+	return &Service{
+		repo:      repo,
+		validator: validator,
+	}
+}
+
+// CreatePage registers the metadata of a page. Blocks may already exist for its slug, e.g. pages written before page
+// metadata existed.
+func (s *Service) CreatePage(ctx context.Context, req *dtos.CreatePageRequest) (*dtos.PageDto, error) {
+	page := req.ToEntity()
+	if err := s.validator.ValidateStruct(page); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, page); err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, errors.ConflictM(msgkey.ErrPageSlugAlreadyUsed, err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgPageResource), err)
+	}
+
+	return dtos.PageDtoFromEntity(page), nil
+}
+
+// UpdatePage replaces the metadata of a page, and its sections when given.
+func (s *Service) UpdatePage(ctx context.Context, req *dtos.UpdatePageRequest) (*dtos.PageDto, error) {
+	page, err := s.find(ctx, req.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(page)
+	return s.save(ctx, page)
+}
+
+// ReorderSections changes the order of the sections of a page, without adding or removing any.
+func (s *Service) ReorderSections(ctx context.Context, req *dtos.ReorderSectionsRequest) (*dtos.PageDto, error) {
+	page, err := s.find(ctx, req.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	current := slices.Sorted(slices.Values(page.Sections))
+	requested := slices.Sorted(slices.Values(req.Sections))
+	if !slices.Equal(current, requested) {
+		return nil, errors.ValidationErrors(map[string]string{"Sections": loc.L(msgkey.ErrSectionsMismatch)})
+	}
+
+	page.Sections = req.Sections
+	page.UpdatedAt = time.Now()
+	return s.save(ctx, page)
+}
+
+// DeletePage removes the metadata of a page. Its blocks are kept, and served in no particular order.
+func (s *Service) DeletePage(ctx context.Context, req *dtos.PageRequest) error {
+	if err := s.repo.Delete(ctx, req.Slug); err != nil {
+		if database.IsNotFound(err) {
+			return errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgPageResource), err)
+		}
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceDeleted, msgkey.MsgPageResource), err)
+	}
+
+	return nil
+}
+
+// GetPage returns the metadata of a page by slug.
+func (s *Service) GetPage(ctx context.Context, req *dtos.PageRequest) (*dtos.PageDto, error) {
+	page, err := s.find(ctx, req.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return dtos.PageDtoFromEntity(page), nil
+}
+
+// GetPages returns the metadata of every page, by slug.
+func (s *Service) GetPages(ctx context.Context) (*dtos.GetPagesResponse, error) {
+	pages, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgPageResource), err)
+	}
+
+	return dtos.GetPagesResponseFromEntity(pages), nil
+}
+
+func (s *Service) save(ctx context.Context, page *entities.Page) (*dtos.PageDto, error) {
+	if err := s.validator.ValidateStruct(page); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, page); err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgPageResource), err)
+	}
+
+	return dtos.PageDtoFromEntity(page), nil
+}
+
+func (s *Service) find(ctx context.Context, slug string) (*entities.Page, error) {
+	page, err := s.repo.FindBySlug(ctx, slug)
+	if err != nil {
+		if database.IsNotFound(err) {
+			return nil, errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgPageResource), err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgPageResource), err)
+	}
+	return page, nil
+}
@@ -0,0 +1,86 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"company-name/pkg/idgenerator"
+	"time"
+)
+
+type CreatePreviewRequest struct {
+	Page      string     `json:"-" validate:"required"`
+	Note      string     `json:"note" validate:"max=200"`
+	ExpiresAt *time.Time `json:"expires_at"` // Default expiry of preview links when omitted
+	CreatedBy string     `json:"-"`
+}
+
+func (req *CreatePreviewRequest) ToEntity(expiresAt time.Time) *entities.PagePreview {
+	return &entities.PagePreview{
+		ID:        idgenerator.GenerateID(),
+		Page:      req.Page,
+		Note:      req.Note,
+		CreatedBy: req.CreatedBy,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+// PagePreviewsRequest identifies the page whose previews are listed.
+type PagePreviewsRequest struct {
+	Page string `json:"-" validate:"required"`
+}
+
+// PreviewRequest identifies the preview of a page to revoke.
+type PreviewRequest struct {
+	Page string `json:"-" validate:"required"`
+	ID   string `json:"-" validate:"required"`
+}
+
+// PreviewDto is a preview link. The link and its token are only given while they can be used.
+type PreviewDto struct {
+	ID        string     `json:"id"`
+	Page      string     `json:"page"`
+	Note      string     `json:"note"`
+	CreatedBy string     `json:"created_by"`
+	Token     string     `json:"token,omitempty"`
+	Link      string     `json:"link,omitempty"`
+	Revoked   bool       `json:"revoked"`
+	Expired   bool       `json:"expired"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func PreviewDtoFromEntity(entity *entities.PagePreview) *PreviewDto {
+	return &PreviewDto{
+		ID:        entity.ID.Hex(),
+		Page:      entity.Page,
+		Note:      entity.Note,
+		CreatedBy: entity.CreatedBy,
+		Revoked:   entity.IsRevoked(),
+		Expired:   entity.IsExpired(time.Now()),
+		ExpiresAt: entity.ExpiresAt,
+		RevokedAt: entity.RevokedAt,
+		CreatedAt: entity.CreatedAt,
+	}
+}
+
+// InLocation renders the timestamps of the preview in the given location.
+func (dto *PreviewDto) InLocation(location *time.Location) {
+	dto.ExpiresAt = dto.ExpiresAt.In(location)
+	if dto.RevokedAt != nil {
+		revokedAt := dto.RevokedAt.In(location)
+		dto.RevokedAt = &revokedAt
+	}
+	dto.CreatedAt = dto.CreatedAt.In(location)
+}
+
+type GetPreviewsResponse struct {
+	Previews []PreviewDto `json:"previews"`
+}
+
+// InLocation renders the timestamps of every preview in the given location.
+func (res *GetPreviewsResponse) InLocation(location *time.Location) {
+	for i := range res.Previews {
+		res.Previews[i].InLocation(location)
+	}
+}
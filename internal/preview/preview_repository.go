@@ -0,0 +1,71 @@
+package preview
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/database"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IPreviewRepository defines the interface for the preview links of pages
+type IPreviewRepository interface {
+	Create(ctx context.Context, preview *entities.PagePreview) error
+	Revoke(ctx context.Context, preview *entities.PagePreview, revokedAt time.Time) error
+	FindByID(ctx context.Context, id string) (*entities.PagePreview, error)
+	FindByPage(ctx context.Context, page string) ([]*entities.PagePreview, error)
+}
+
+type Repository struct {
+	db database.IDatabase
+}
+
+// NewPreviewRepository initializes a new preview repository
+func NewPreviewRepository(db database.IDatabase) IPreviewRepository {
+	return &Repository{db: db}
+}
+
+// Create records a new preview
+func (r *Repository) Create(ctx context.Context, preview *entities.PagePreview) error {
+	return r.db.Create(ctx, constants.DbPagePreviewsCollection, preview)
+}
+
+// Revoke marks a preview as revoked, unless it already is
+func (r *Repository) Revoke(ctx context.Context, preview *entities.PagePreview, revokedAt time.Time) error {
+	filter := bson.M{"_id": preview.ID, "revoked_at": nil}
+	update := bson.M{"$set": bson.M{"revoked_at": revokedAt}}
+
+	if err := r.db.Update(ctx, constants.DbPagePreviewsCollection, filter, update); err != nil {
+		return err
+	}
+	preview.RevokedAt = &revokedAt
+	return nil
+}
+
+// FindByID retrieves a preview by its ID
+func (r *Repository) FindByID(ctx context.Context, id string) (*entities.PagePreview, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var preview entities.PagePreview
+	if err := r.db.FindOne(ctx, constants.DbPagePreviewsCollection, bson.M{"_id": objectID}, &preview); err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+// FindByPage retrieves the previews of a page, revoked and expired ones included, most recent first
+func (r *Repository) FindByPage(ctx context.Context, page string) ([]*entities.PagePreview, error) {
+	previews := []*entities.PagePreview{}
+	filter := bson.M{"page": page}
+
+	if err := r.db.FindWithPagination(ctx, constants.DbPagePreviewsCollection, filter, "created_at", constants.SortDesc, 0, 0, &previews); err != nil {
+		return nil, err
+	}
+	return previews, nil
+}
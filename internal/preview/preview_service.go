@@ -0,0 +1,137 @@
+package preview
+
+import (
+	"company-name/configs"
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/entities"
+	"company-name/internal/page"
+	"company-name/internal/preview/dtos"
+	"company-name/pkg/database"
+	"company-name/pkg/errors"
+	"company-name/pkg/jwttoken"
+	loc "company-name/pkg/localization"
+	"context"
+	goerrors "errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// IPreviewService issues the links letting people without an account read the drafts of a page, lists and revokes
+// them, and checks the tokens of the links when pages are read.
+type IPreviewService interface {
+	CreatePreview(ctx context.Context, req *dtos.CreatePreviewRequest) (*dtos.PreviewDto, error)
+	GetPreviews(ctx context.Context, req *dtos.PagePreviewsRequest) (*dtos.GetPreviewsResponse, error)
+	RevokePreview(ctx context.Context, req *dtos.PreviewRequest) error
+	AuthorizePreview(ctx context.Context, token, page string) error
+}
+
+type Service struct {
+	repo   IPreviewRepository
+	pages  page.IPageRepository
+	config *configs.Config
+}
+
+// NewPreviewService initializes a new preview service
+func NewPreviewService(repo IPreviewRepository, pages page.IPageRepository, config *configs.Config) IPreviewService {
+	return &Service{
+		repo:   repo,
+		pages:  pages,
+		config: config,
+	}
+}
+
+// CreatePreview issues a preview link for a page, valid until the requested time or for the default duration.
+func (s *Service) CreatePreview(ctx context.Context, req *dtos.CreatePreviewRequest) (*dtos.PreviewDto, error) {
+	if _, err := s.pages.FindBySlug(ctx, req.Page); err != nil {
+		if database.IsNotFound(err) {
+			return nil, errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgPageResource), err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgPageResource), err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(s.config.JWT.PreviewExpiration) * time.Millisecond)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, errors.ValidationErrors(map[string]string{"ExpiresAt": loc.L(msgkey.ErrPreviewExpiryInPast)})
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	// Tokens only carry whole seconds, the expiry is stored likewise so that the token can be derived again
+	preview := req.ToEntity(expiresAt.Truncate(time.Second))
+	if err := s.repo.Create(ctx, preview); err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgPreviewResource), err)
+	}
+
+	return s.previewDto(preview), nil
+}
+
+// GetPreviews lists the previews of a page, most recent first.
+func (s *Service) GetPreviews(ctx context.Context, req *dtos.PagePreviewsRequest) (*dtos.GetPreviewsResponse, error) {
+	previews, err := s.repo.FindByPage(ctx, req.Page)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgPreviewResource), err)
+	}
+
+	response := &dtos.GetPreviewsResponse{Previews: make([]dtos.PreviewDto, 0, len(previews))}
+	for _, preview := range previews {
+		response.Previews = append(response.Previews, *s.previewDto(preview))
+	}
+	return response, nil
+}
+
+// RevokePreview stops the link of a preview from working before it expires. Revoking it again has no effect.
+func (s *Service) RevokePreview(ctx context.Context, req *dtos.PreviewRequest) error {
+	preview, err := s.repo.FindByID(ctx, req.ID)
+	if err != nil || preview.Page != req.Page {
+		return errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgPreviewResource), err)
+	}
+	if preview.IsRevoked() {
+		return nil
+	}
+
+	if err := s.repo.Revoke(ctx, preview, time.Now()); err != nil && !database.IsNotFound(err) {
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgPreviewResource), err)
+	}
+	return nil
+}
+
+// AuthorizePreview checks that a token is the one of a preview of the page, neither revoked nor expired. Previews are
+// looked up in the tenant of the request, so that a link only works on the site it was issued for.
+func (s *Service) AuthorizePreview(ctx context.Context, token, page string) error {
+	claims, err := jwttoken.ValidateTypedToken(token, constants.PreviewTokenType)
+	if err != nil {
+		if goerrors.Is(err, jwt.ErrTokenExpired) {
+			return errors.UnauthorizedM(msgkey.ErrPreviewExpired, err)
+		}
+		return errors.UnauthorizedM(msgkey.ErrInvalidPreviewToken, err)
+	}
+
+	previewID, _ := claims["sub"].(string)
+	preview, err := s.repo.FindByID(ctx, previewID)
+	if err != nil {
+		return errors.UnauthorizedM(msgkey.ErrInvalidPreviewToken, err)
+	}
+	if preview.Page != page || preview.IsRevoked() {
+		return errors.UnauthorizedM(msgkey.ErrInvalidPreviewToken, nil)
+	}
+	if preview.IsExpired(time.Now()) {
+		return errors.UnauthorizedM(msgkey.ErrPreviewExpired, nil)
+	}
+	return nil
+}
+
+// previewDto converts a preview, with its link while it can be used.
+func (s *Service) previewDto(preview *entities.PagePreview) *dtos.PreviewDto {
+	dto := dtos.PreviewDtoFromEntity(preview)
+	if !dto.Revoked && !dto.Expired {
+		dto.Token = jwttoken.GenerateTypedToken(preview.ID.Hex(), constants.PreviewTokenType, preview.ID.Hex(), preview.ExpiresAt)
+		dto.Link = fmt.Sprintf("%s/%s?preview=%s", s.config.App.PreviewUrl, url.PathEscape(preview.Page), dto.Token)
+	}
+	return dto
+}
@@ -0,0 +1,24 @@
+package dtos
+
+import "company-name/entities"
+
+type UpdateAvatarRequest struct {
+	ID              string `json:"id" validate:"required"`
+	Data            []byte `json:"-"`
+	ExpectedVersion int64  `json:"-"`
+}
+
+type UpdateAvatarResponse struct {
+	UserDto
+}
+
+func UpdateAvatarResponseFromEntity(user *entities.User) *UpdateAvatarResponse {
+	return &UpdateAvatarResponse{
+		UserDto: *UserDtoFromEntity(user),
+	}
+}
+
+type DeleteAvatarRequest struct {
+	ID              string `json:"id" validate:"required"`
+	ExpectedVersion int64  `json:"-"`
+}
@@ -0,0 +1,69 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"time"
+)
+
+type ChangeUserStatusRequest struct {
+	ID        string `json:"-" validate:"required"`
+	Reason    string `json:"reason" validate:"required,min=3,max=500"`
+	ChangedBy string `json:"-"`
+}
+
+type ChangeUserStatusResponse struct {
+	UserDto
+}
+
+func ChangeUserStatusResponseFromEntity(user *entities.User) *ChangeUserStatusResponse {
+	return &ChangeUserStatusResponse{
+		UserDto: *UserDtoFromEntity(user),
+	}
+}
+
+type UserStatusChangeDto struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	ChangedBy  string    `json:"changed_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func UserStatusChangeDtoFromEntity(entity *entities.UserStatusChange) *UserStatusChangeDto {
+	return &UserStatusChangeDto{
+		FromStatus: entity.FromStatus,
+		ToStatus:   entity.ToStatus,
+		Reason:     entity.Reason,
+		ChangedBy:  entity.ChangedBy,
+		CreatedAt:  entity.CreatedAt,
+	}
+}
+
+// InLocation renders the timestamp of the change in the given location.
+func (dto *UserStatusChangeDto) InLocation(location *time.Location) {
+	dto.CreatedAt = dto.CreatedAt.In(location)
+}
+
+type GetUserStatusHistoryRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type GetUserStatusHistoryResponse struct {
+	History []UserStatusChangeDto `json:"history"`
+}
+
+func GetUserStatusHistoryResponseFromEntity(changes []*entities.UserStatusChange) *GetUserStatusHistoryResponse {
+	history := make([]UserStatusChangeDto, 0, len(changes))
+	for _, change := range changes {
+		history = append(history, *UserStatusChangeDtoFromEntity(change))
+	}
+
+	return &GetUserStatusHistoryResponse{History: history}
+}
+
+// InLocation renders the timestamps of every change in the given location.
+func (res *GetUserStatusHistoryResponse) InLocation(location *time.Location) {
+	for i := range res.History {
+		res.History[i].InLocation(location)
+	}
+}
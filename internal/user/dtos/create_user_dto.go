@@ -1,6 +1,7 @@
 package dtos
 
 import (
+	"company-name/constants"
 	"company-name/entities"
 	"company-name/pkg/idgenerator"
 	"company-name/pkg/validators"
@@ -27,6 +28,9 @@ func (req *CreateUserRequest) ToEntity() (user *entities.User, password string)
 		FirstName:   req.FirstName,
 		LastName:    req.LastName,
 		PhoneNumber: req.PhoneNumber,
+		Role:        req.Role,
+		Status:      constants.UserStatusPending,
+		Version:     1,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -1,7 +1,8 @@
 package dtos
 
 type DeleteUserRequest struct {
-	ID string `json:"id"`
+	ID              string `json:"id"`
+	ExpectedVersion int64  `json:"-"`
 }
 
 type DeleteUserResponse struct {
@@ -15,7 +15,7 @@ type GetPaginatedUsersResponse struct {
 }
 
 func GetPaginatedUsersResponseFromEntity(users []*entities.User, totalCount int) *GetPaginatedUsersResponse {
-	userDtos := make([]UserDto, len(users))
+	userDtos := make([]UserDto, 0, len(users))
 	for _, user := range users {
 		userDtos = append(userDtos, *UserDtoFromEntity(user))
 	}
@@ -0,0 +1,25 @@
+package dtos
+
+import "company-name/entities"
+
+type UpdatePreferencesRequest struct {
+	ID                string `json:"-" validate:"required"`
+	PreferredLanguage string `json:"preferred_language" validate:"omitempty,language"`
+	Timezone          string `json:"timezone" validate:"omitempty,timezone"`
+	ExpectedVersion   int64  `json:"-"`
+}
+
+func (req *UpdatePreferencesRequest) ApplyTo(user *entities.User) {
+	user.PreferredLanguage = req.PreferredLanguage
+	user.Timezone = req.Timezone
+}
+
+type UpdatePreferencesResponse struct {
+	UserDto
+}
+
+func UpdatePreferencesResponseFromEntity(user *entities.User) *UpdatePreferencesResponse {
+	return &UpdatePreferencesResponse{
+		UserDto: *UserDtoFromEntity(user),
+	}
+}
@@ -0,0 +1,47 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"company-name/pkg/utils/textsearch"
+)
+
+type SearchUsersRequest struct {
+	Query    string `form:"q" validate:"required,max=100" binding:"required"`
+	Page     int    `form:"page" validate:"required,min=1" binding:"required"`
+	PageSize int    `form:"page_size" validate:"required,min=1,max=100" binding:"required"`
+}
+
+// SearchUserDto is a matching user along with its relevance and, for every searched field containing a matched word,
+// the field's value with those words highlighted.
+type SearchUserDto struct {
+	UserDto
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+type SearchUsersResponse struct {
+	Users []SearchUserDto `json:"users"`
+	Total int             `json:"total"`
+}
+
+func SearchUserDtoFromEntity(user *entities.User, score float64, terms []string) *SearchUserDto {
+	fields := map[string]string{
+		"first_name":   user.FirstName,
+		"last_name":    user.LastName,
+		"email":        user.Email,
+		"phone_number": user.PhoneNumber,
+	}
+
+	highlights := make(map[string]string)
+	for field, value := range fields {
+		if highlighted, matched := textsearch.Highlight(value, terms); matched {
+			highlights[field] = highlighted
+		}
+	}
+
+	return &SearchUserDto{
+		UserDto:    *UserDtoFromEntity(user),
+		Score:      score,
+		Highlights: highlights,
+	}
+}
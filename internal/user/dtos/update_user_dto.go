@@ -2,7 +2,6 @@ package dtos
 
 import (
 	"company-name/entities"
-	"company-name/pkg/idgenerator"
 	"company-name/pkg/validators"
 )
 
@@ -13,21 +12,17 @@ type UpdateUserRequest struct {
 	Password    string `json:"password" validate:"omitempty,min=8,max=100"`
 	Email       string `json:"email" validate:"required,email"`
 	PhoneNumber string `json:"phone_number" validate:"required,min=11,max=11"`
-}
 
-func (req *UpdateUserRequest) ToEntity() (*entities.User, string, error) {
-	userId, err := idgenerator.ToPersistenceID(req.ID)
-	if err != nil {
-		return nil, "", err
-	}
+	ExpectedVersion int64 `json:"-"`
+}
 
-	return &entities.User{
-		ID:          userId,
-		FirstName:   req.FirstName,
-		LastName:    req.LastName,
-		Email:       req.Email,
-		PhoneNumber: req.PhoneNumber,
-	}, req.Password, nil
+// ApplyTo copies the editable fields of the request onto an existing user and returns the new password, if any.
+func (req *UpdateUserRequest) ApplyTo(user *entities.User) string {
+	user.FirstName = req.FirstName
+	user.LastName = req.LastName
+	user.Email = req.Email
+	user.PhoneNumber = req.PhoneNumber
+	return req.Password
 }
 
 func (u *UpdateUserRequest) Validate(validator validators.IValidator) error {
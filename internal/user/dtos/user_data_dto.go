@@ -0,0 +1,72 @@
+package dtos
+
+import (
+	"company-name/entities"
+	filesDtos "company-name/internal/files/dtos"
+	"time"
+)
+
+type ExportUserDataRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type UserProfileDto struct {
+	UserDto
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SessionDto struct {
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ExportUserDataResponse is the archive of every piece of data tied to a user, returned for data-subject requests.
+type ExportUserDataResponse struct {
+	ExportedAt    time.Time             `json:"exported_at"`
+	Profile       UserProfileDto        `json:"profile"`
+	Files         []filesDtos.FileDto   `json:"files"`
+	Sessions      []SessionDto          `json:"sessions"`
+	StatusHistory []UserStatusChangeDto `json:"status_history"`
+}
+
+func ExportUserDataResponseFromEntities(
+	user *entities.User,
+	files []*entities.File,
+	sessions []*entities.Session,
+	changes []*entities.UserStatusChange,
+) *ExportUserDataResponse {
+	response := &ExportUserDataResponse{
+		ExportedAt: time.Now(),
+		Profile: UserProfileDto{
+			UserDto:   *UserDtoFromEntity(user),
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+		},
+		Files:         make([]filesDtos.FileDto, 0, len(files)),
+		Sessions:      make([]SessionDto, 0, len(sessions)),
+		StatusHistory: GetUserStatusHistoryResponseFromEntity(changes).History,
+	}
+
+	for _, file := range files {
+		response.Files = append(response.Files, *filesDtos.FileDtoFromEntity(file))
+	}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, SessionDto{
+			IPAddress: session.IPAddress,
+			UserAgent: session.UserAgent,
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+		})
+	}
+
+	return response
+}
+
+type EraseUserRequest struct {
+	ID       string `json:"-" validate:"required"`
+	Reason   string `json:"reason" validate:"required,min=3,max=500"`
+	ErasedBy string `json:"-"`
+}
@@ -5,19 +5,31 @@ import (
 )
 
 type UserDto struct {
-	ID          string `json:"id"`
-	Email       string `json:"email"`
-	FirstName   string `json:"first_name"`
-	LastName    string `json:"last_name"`
-	PhoneNumber string `json:"phone_number"`
+	ID                string `json:"id"`
+	Email             string `json:"email"`
+	FirstName         string `json:"first_name"`
+	LastName          string `json:"last_name"`
+	PhoneNumber       string `json:"phone_number"`
+	AvatarURL         string `json:"avatar_url"`
+	PreferredLanguage string `json:"preferred_language"`
+	Timezone          string `json:"timezone"`
+	Role              string `json:"role"`
+	Status            string `json:"status"`
+	Version           int64  `json:"version"`
 }
 
 func UserDtoFromEntity(entity *entities.User) *UserDto {
 	return &UserDto{
-		ID:          entity.ID.Hex(),
-		Email:       entity.Email,
-		FirstName:   entity.FirstName,
-		LastName:    entity.LastName,
-		PhoneNumber: entity.PhoneNumber,
+		ID:                entity.ID.Hex(),
+		Email:             entity.Email,
+		FirstName:         entity.FirstName,
+		LastName:          entity.LastName,
+		PhoneNumber:       entity.PhoneNumber,
+		AvatarURL:         entity.AvatarURL,
+		PreferredLanguage: entity.PreferredLanguage,
+		Timezone:          entity.Timezone,
+		Role:              entity.Role,
+		Status:            entity.Status,
+		Version:           entity.Version,
 	}
 }
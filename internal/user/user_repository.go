@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"regexp"
 	"time"
 
 	"company-name/constants"
@@ -18,17 +19,34 @@ import (
 type IUserRepository interface {
 	Create(ctx context.Context, user *entities.User) error
 	Update(ctx context.Context, user *entities.User) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, user *entities.User) error
 	FindByEmail(ctx context.Context, email string) (*entities.User, error)
 	FindByID(ctx context.Context, id string) (*entities.User, error)
 	FindAll(ctx context.Context) ([]*entities.User, error)
 	FindAllPaginated(ctx context.Context, filter string, page, pageSize int, sortBy, sortOrder string) ([]*entities.User, int64, error)
+	Search(ctx context.Context, query string, page, pageSize int) ([]*SearchHit, int64, error)
+	UpdateStatus(ctx context.Context, user *entities.User) error
+	UpdateAvatar(ctx context.Context, user *entities.User) error
+	UpdatePreferences(ctx context.Context, user *entities.User) error
+	CreateStatusChange(ctx context.Context, change *entities.UserStatusChange) error
+	FindStatusChanges(ctx context.Context, userID primitive.ObjectID) ([]*entities.UserStatusChange, error)
+	FindSessions(ctx context.Context, userID primitive.ObjectID) ([]*entities.Session, error)
+	DeleteSessions(ctx context.Context, userID primitive.ObjectID) error
+	FindByAvatar(ctx context.Context, avatarURL string) ([]*entities.User, error)
+	FindAvatarURLs(ctx context.Context) (map[string]bool, error)
+	WithTransaction(ctx context.Context, function func(ctx context.Context) error) error
 }
 
 type Repository struct {
 	db database.IDatabase
 }
 
+// SearchHit is a user matching a full-text search, along with the relevance computed by the text index.
+type SearchHit struct {
+	entities.User `bson:",inline"`
+	Score         float64 `bson:"score"`
+}
+
 // NewUserRepository initializes a new UserRepository
 func NewUserRepository(db database.IDatabase) IUserRepository {
 	return &Repository{db: db}
@@ -42,26 +60,24 @@ func (r *Repository) Create(ctx context.Context, user *entities.User) error {
 	return nil
 }
 
-// Update modifies an existing user's details in the database
+// Update modifies an existing user's details in the database, provided it is still at the version it was read at
 func (r *Repository) Update(ctx context.Context, user *entities.User) error {
+	expectedVersion := user.Version
+	user.Version = expectedVersion + 1
 	user.UpdatedAt = time.Now()
-	filter := bson.M{"_id": user.ID}
+	filter := bson.M{"_id": user.ID, "version": database.VersionFilter(expectedVersion)}
 	update := bson.M{"$set": user}
 
 	if err := r.db.Update(ctx, constants.DbUsersCollection, filter, update); err != nil {
+		user.Version = expectedVersion
 		return err
 	}
 	return nil
 }
 
-// Delete removes a user from the database by ID
-func (r *Repository) Delete(ctx context.Context, id string) error {
-	objectId, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return errors.New("invalid user ID")
-	}
-
-	filter := bson.M{"_id": objectId}
+// Delete removes a user from the database, provided it is still at the version it was read at
+func (r *Repository) Delete(ctx context.Context, user *entities.User) error {
+	filter := bson.M{"_id": user.ID, "version": database.VersionFilter(user.Version)}
 	if err := r.db.Delete(ctx, constants.DbUsersCollection, filter); err != nil {
 		return err
 	}
@@ -139,11 +155,12 @@ func (r *Repository) FindAll(ctx context.Context) ([]*entities.User, error) {
 func (r *Repository) FindAllPaginated(ctx context.Context, filter string, page, pageSize int, sortBy, sortOrder string) ([]*entities.User, int64, error) {
 	searchFilter := bson.M{}
 	if filter != "" {
+		pattern := regexp.QuoteMeta(filter)
 		searchFilter["$or"] = bson.A{
-			bson.M{"firstName": bson.M{"$regex": filter, "$options": "i"}},
-			bson.M{"lastName": bson.M{"$regex": filter, "$options": "i"}},
-			bson.M{"email": bson.M{"$regex": filter, "$options": "i"}},
-			bson.M{"phoneNumber": bson.M{"$regex": filter, "$options": "i"}},
+			bson.M{"first_name": bson.M{"$regex": pattern, "$options": "i"}},
+			bson.M{"last_name": bson.M{"$regex": pattern, "$options": "i"}},
+			bson.M{"email": bson.M{"$regex": pattern, "$options": "i"}},
+			bson.M{"phone_number": bson.M{"$regex": pattern, "$options": "i"}},
 		}
 	}
 
@@ -161,3 +178,130 @@ func (r *Repository) FindAllPaginated(ctx context.Context, filter string, page,
 
 	return users, totalCount, nil
 }
+
+// Search retrieves a page of the users matching a full-text query over their name, email and phone number, most
+// relevant first. Erased users are left out.
+func (r *Repository) Search(ctx context.Context, query string, page, pageSize int) ([]*SearchHit, int64, error) {
+	filter := bson.M{
+		"$text":  bson.M{"$search": query},
+		"status": bson.M{"$ne": constants.UserStatusErased},
+	}
+
+	totalCount, err := r.db.Count(ctx, constants.DbUsersCollection, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+
+	var hits []*SearchHit
+	if err := r.db.FindByTextScore(ctx, constants.DbUsersCollection, filter, int64(offset), int64(pageSize), &hits); err != nil {
+		return nil, 0, err
+	}
+
+	return hits, totalCount, nil
+}
+
+// UpdateStatus persists only the status of the given user
+func (r *Repository) UpdateStatus(ctx context.Context, user *entities.User) error {
+	return r.updateFields(ctx, user, bson.M{"status": user.Status})
+}
+
+// UpdateAvatar persists only the avatar of the given user
+func (r *Repository) UpdateAvatar(ctx context.Context, user *entities.User) error {
+	return r.updateFields(ctx, user, bson.M{"avatar_url": user.AvatarURL})
+}
+
+// UpdatePreferences persists only the language and timezone preferences of the given user
+func (r *Repository) UpdatePreferences(ctx context.Context, user *entities.User) error {
+	return r.updateFields(ctx, user, bson.M{"preferred_language": user.PreferredLanguage, "timezone": user.Timezone})
+}
+
+// updateFields sets the given fields of the user, provided it is still at the version it was read at, and moves it
+// to the next version
+func (r *Repository) updateFields(ctx context.Context, user *entities.User, fields bson.M) error {
+	updatedAt := time.Now()
+	fields["updated_at"] = updatedAt
+	fields["version"] = user.Version + 1
+	filter := bson.M{"_id": user.ID, "version": database.VersionFilter(user.Version)}
+
+	if err := r.db.Update(ctx, constants.DbUsersCollection, filter, bson.M{"$set": fields}); err != nil {
+		return err
+	}
+
+	user.UpdatedAt = updatedAt
+	user.Version++
+	return nil
+}
+
+// CreateStatusChange appends an entry to the status history of a user
+func (r *Repository) CreateStatusChange(ctx context.Context, change *entities.UserStatusChange) error {
+	if err := r.db.Create(ctx, constants.DbUserStatusChangesCollection, change); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindStatusChanges retrieves the status history of a user, oldest entry first
+func (r *Repository) FindStatusChanges(ctx context.Context, userID primitive.ObjectID) ([]*entities.UserStatusChange, error) {
+	changes := []*entities.UserStatusChange{}
+	filter := bson.M{"user_id": userID}
+
+	if err := r.db.FindWithPagination(ctx, constants.DbUserStatusChangesCollection, filter, "created_at", constants.SortAsc, 0, 0, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// FindSessions retrieves every login session of a user
+func (r *Repository) FindSessions(ctx context.Context, userID primitive.ObjectID) ([]*entities.Session, error) {
+	sessions := []*entities.Session{}
+	filter := bson.M{"user_id": userID}
+
+	if err := r.db.Find(ctx, constants.DbSessionsCollection, filter, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// DeleteSessions removes every login session of a user
+func (r *Repository) DeleteSessions(ctx context.Context, userID primitive.ObjectID) error {
+	filter := bson.M{"user_id": userID}
+
+	if err := r.db.DeleteAll(ctx, constants.DbSessionsCollection, filter); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindByAvatar retrieves the users whose avatar is the stored file at the given path
+func (r *Repository) FindByAvatar(ctx context.Context, avatarURL string) ([]*entities.User, error) {
+	users := []*entities.User{}
+	if err := r.db.Find(ctx, constants.DbUsersCollection, bson.M{"avatar_url": avatarURL}, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// FindAvatarURLs retrieves the paths of the stored files used as avatars
+func (r *Repository) FindAvatarURLs(ctx context.Context) (map[string]bool, error) {
+	users := []*entities.User{}
+	filter := bson.M{"avatar_url": bson.M{"$nin": bson.A{"", nil}}}
+	if err := r.db.Find(ctx, constants.DbUsersCollection, filter, &users); err != nil {
+		return nil, err
+	}
+
+	avatarURLs := make(map[string]bool, len(users))
+	for _, user := range users {
+		avatarURLs[user.AvatarURL] = true
+	}
+	return avatarURLs, nil
+}
+
+// WithTransaction runs function in a transaction. The operations made with the context it is given, through this
+// repository or any other sharing the database, are committed together or not at all
+func (r *Repository) WithTransaction(ctx context.Context, function func(ctx context.Context) error) error {
+	return r.db.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		return function(sessCtx)
+	})
+}
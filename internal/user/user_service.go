@@ -1,13 +1,29 @@
 package user
 
 import (
+	"company-name/constants"
 	"company-name/constants/msgkey"
+	"company-name/entities"
+	"company-name/internal/files"
 	"company-name/internal/user/dtos"
+	"company-name/pkg/database"
+	"company-name/pkg/email"
 	"company-name/pkg/errors"
+	"company-name/pkg/file"
 	"company-name/pkg/hasher"
+	"company-name/pkg/idgenerator"
 	loc "company-name/pkg/localization"
+	"company-name/pkg/utils/etag"
+	"company-name/pkg/utils/textsearch"
 	"company-name/pkg/validators"
 	"context"
+	goerrors "errors"
+	"log"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
 )
 
 type IUserService interface {
@@ -16,15 +32,34 @@ type IUserService interface {
 	DeleteUser(ctx context.Context, req *dtos.DeleteUserRequest) error
 	GetUserDetailsById(ctx context.Context, req *dtos.GetUserDetailsRequest) (*dtos.GetUserDetailsResponse, error)
 	GetPaginatedUsers(ctx context.Context, dto *dtos.GetPaginatedUsersRequest) (*dtos.GetPaginatedUsersResponse, error)
+	SearchUsers(ctx context.Context, req *dtos.SearchUsersRequest) (*dtos.SearchUsersResponse, error)
+	BlockUser(ctx context.Context, req *dtos.ChangeUserStatusRequest) (*dtos.ChangeUserStatusResponse, error)
+	UnblockUser(ctx context.Context, req *dtos.ChangeUserStatusRequest) (*dtos.ChangeUserStatusResponse, error)
+	ActivateUser(ctx context.Context, req *dtos.ChangeUserStatusRequest) (*dtos.ChangeUserStatusResponse, error)
+	GetUserStatusHistory(ctx context.Context, req *dtos.GetUserStatusHistoryRequest) (*dtos.GetUserStatusHistoryResponse, error)
+	UpdateAvatar(ctx context.Context, req *dtos.UpdateAvatarRequest) (*dtos.UpdateAvatarResponse, error)
+	DeleteAvatar(ctx context.Context, req *dtos.DeleteAvatarRequest) error
+	UpdatePreferences(ctx context.Context, req *dtos.UpdatePreferencesRequest) (*dtos.UpdatePreferencesResponse, error)
+	ExportUserData(ctx context.Context, req *dtos.ExportUserDataRequest) (*dtos.ExportUserDataResponse, error)
+	EraseUser(ctx context.Context, req *dtos.EraseUserRequest) error
 }
 
 type Service struct {
-	repo      IUserRepository
-	validator validators.IValidator
+	repo         IUserRepository
+	validator    validators.IValidator
+	emailService email.IEmailService
+	fileService  *file.FileService
+	filesRepo    files.IFileRepository
 }
 
-func NewUserService(repo IUserRepository, validator validators.IValidator) IUserService {
-	return &Service{repo, validator}
+func NewUserService(
+	repo IUserRepository,
+	validator validators.IValidator,
+	emailService email.IEmailService,
+	fileService *file.FileService,
+	filesRepo files.IFileRepository,
+) IUserService {
+	return &Service{repo, validator, emailService, fileService, filesRepo}
 }
 
 func (s *Service) CreateUser(ctx context.Context, req *dtos.CreateUserRequest) (*dtos.CreateUserResponse, error) {
@@ -43,20 +78,23 @@ func (s *Service) CreateUser(ctx context.Context, req *dtos.CreateUserRequest) (
 
 	err = s.repo.Create(ctx, user)
 	if err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, errors.ConflictM(msgkey.ErrEmailAlreadyUsed, err)
+		}
 		return nil, errors.InternalServerErrorM("Failed to create user", err)
 	}
 
 	response := dtos.CreateUserResponseFromEntity(user)
 	return response, nil
-	panic("not implemented")
 }
 
 func (s *Service) UpdateUser(ctx context.Context, req *dtos.UpdateUserRequest) (*dtos.UpdateUserResponse, error) {
-	user, password, err := req.ToEntity()
+	user, err := s.findAtVersion(ctx, req.ID, req.ExpectedVersion)
 	if err != nil {
 		return nil, err
 	}
 
+	password := req.ApplyTo(user)
 	if password != "" {
 		hashedPassword, err := hasher.HashPassword(password)
 		if err != nil {
@@ -67,6 +105,12 @@ func (s *Service) UpdateUser(ctx context.Context, req *dtos.UpdateUserRequest) (
 
 	err = s.repo.Update(ctx, user)
 	if err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, errors.ConflictM(msgkey.ErrEmailAlreadyUsed, err)
+		}
+		if database.IsNotFound(err) {
+			return nil, s.concurrentWriteError(ctx, req.ID, err)
+		}
 		return nil, errors.InternalServerErrorM("Failed to update user", err)
 	}
 
@@ -74,13 +118,21 @@ func (s *Service) UpdateUser(ctx context.Context, req *dtos.UpdateUserRequest) (
 }
 
 func (s *Service) DeleteUser(ctx context.Context, req *dtos.DeleteUserRequest) error {
-	if _, err := s.repo.FindByID(ctx, req.ID); err != nil {
-		return errors.NotFound(err)
+	user, err := s.findAtVersion(ctx, req.ID, req.ExpectedVersion)
+	if err != nil {
+		return err
 	}
 
-	if err := s.repo.Delete(ctx, req.ID); err != nil {
+	if err := s.repo.Delete(ctx, user); err != nil {
+		if database.IsNotFound(err) {
+			return s.concurrentWriteError(ctx, req.ID, err)
+		}
 		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceDeleted, msgkey.MsgUserResource), err)
 	}
+
+	if user.HasAvatar() {
+		s.removeAvatarFile(user.AvatarURL)
+	}
 	return nil
 }
 
@@ -101,3 +153,313 @@ func (s *Service) GetPaginatedUsers(ctx context.Context, dto *dtos.GetPaginatedU
 
 	return dtos.GetPaginatedUsersResponseFromEntity(users, int(totalCount)), nil
 }
+
+// SearchUsers returns a page of the users matching a full-text query, most relevant first, with the matched words of
+// every field highlighted. Matching ignores case and diacritics.
+func (s *Service) SearchUsers(ctx context.Context, req *dtos.SearchUsersRequest) (*dtos.SearchUsersResponse, error) {
+	hits, totalCount, err := s.repo.Search(ctx, req.Query, req.Page, req.PageSize)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgUserResource), err)
+	}
+
+	terms := textsearch.Terms(req.Query)
+	users := make([]dtos.SearchUserDto, 0, len(hits))
+	for _, hit := range hits {
+		users = append(users, *dtos.SearchUserDtoFromEntity(&hit.User, hit.Score, terms))
+	}
+
+	return &dtos.SearchUsersResponse{Users: users, Total: int(totalCount)}, nil
+}
+
+// BlockUser blocks a pending or activated user, refusing any further login or authenticated request.
+func (s *Service) BlockUser(ctx context.Context, req *dtos.ChangeUserStatusRequest) (*dtos.ChangeUserStatusResponse, error) {
+	return s.changeStatus(ctx, req, constants.UserStatusBlocked)
+}
+
+// UnblockUser restores a blocked user to the activated status.
+func (s *Service) UnblockUser(ctx context.Context, req *dtos.ChangeUserStatusRequest) (*dtos.ChangeUserStatusResponse, error) {
+	return s.changeStatus(ctx, req, constants.UserStatusActivated, constants.UserStatusBlocked)
+}
+
+// ActivateUser force-activates a pending user without waiting for the email verification.
+func (s *Service) ActivateUser(ctx context.Context, req *dtos.ChangeUserStatusRequest) (*dtos.ChangeUserStatusResponse, error) {
+	return s.changeStatus(ctx, req, constants.UserStatusActivated, constants.UserStatusPending)
+}
+
+// GetUserStatusHistory returns every status change recorded for the user, oldest first.
+func (s *Service) GetUserStatusHistory(ctx context.Context, req *dtos.GetUserStatusHistoryRequest) (*dtos.GetUserStatusHistoryResponse, error) {
+	user, err := s.repo.FindByID(ctx, req.ID)
+	if err != nil {
+		return nil, errors.NotFound(err)
+	}
+
+	changes, err := s.repo.FindStatusChanges(ctx, user.ID)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgUserStatusHistoryResource), err)
+	}
+
+	return dtos.GetUserStatusHistoryResponseFromEntity(changes), nil
+}
+
+// UpdateAvatar validates the uploaded image, stores it under the user's avatar directory and replaces the previous
+// avatar, whose file is removed.
+func (s *Service) UpdateAvatar(ctx context.Context, req *dtos.UpdateAvatarRequest) (*dtos.UpdateAvatarResponse, error) {
+	if len(req.Data) == 0 {
+		return nil, errors.ValidationErrors(map[string]string{"avatar": loc.L(msgkey.ErrAvatarUploadMissing)})
+	}
+
+	if len(req.Data) > constants.AvatarMaxSizeBytes {
+		return nil, errors.ValidationErrors(map[string]string{"avatar": loc.L(msgkey.ErrAvatarTooLarge, strconv.Itoa(constants.AvatarMaxSizeBytes))})
+	}
+
+	extension, allowed := constants.AvatarAllowedMimeTypes[http.DetectContentType(req.Data)]
+	if !allowed {
+		return nil, errors.ValidationErrors(map[string]string{"avatar": loc.L(msgkey.ErrAvatarInvalidType)})
+	}
+
+	user, err := s.findAtVersion(ctx, req.ID, req.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := filepath.Join(constants.AvatarsDirectory, user.ID.Hex(), idgenerator.GenerateID().Hex()+extension)
+	avatarURL, err := s.fileService.SaveFile(fileName, req.Data)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgAvatarResource), err)
+	}
+
+	previousAvatarURL := user.AvatarURL
+	user.AvatarURL = avatarURL
+	if err := s.repo.UpdateAvatar(ctx, user); err != nil {
+		s.removeAvatarFile(avatarURL)
+		if database.IsNotFound(err) {
+			return nil, s.concurrentWriteError(ctx, req.ID, err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgAvatarResource), err)
+	}
+
+	if previousAvatarURL != "" {
+		s.removeAvatarFile(previousAvatarURL)
+	}
+
+	return dtos.UpdateAvatarResponseFromEntity(user), nil
+}
+
+// DeleteAvatar removes the avatar of the user along with its file.
+func (s *Service) DeleteAvatar(ctx context.Context, req *dtos.DeleteAvatarRequest) error {
+	user, err := s.findAtVersion(ctx, req.ID, req.ExpectedVersion)
+	if err != nil {
+		return err
+	}
+
+	if !user.HasAvatar() {
+		return errors.NotFoundM(msgkey.ErrAvatarNotFound, nil)
+	}
+
+	avatarURL := user.AvatarURL
+	user.AvatarURL = ""
+	if err := s.repo.UpdateAvatar(ctx, user); err != nil {
+		if database.IsNotFound(err) {
+			return s.concurrentWriteError(ctx, req.ID, err)
+		}
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceDeleted, msgkey.MsgAvatarResource), err)
+	}
+
+	s.removeAvatarFile(avatarURL)
+	return nil
+}
+
+// UpdatePreferences stores the language and timezone the user wants the API to use when the request does not say.
+func (s *Service) UpdatePreferences(ctx context.Context, req *dtos.UpdatePreferencesRequest) (*dtos.UpdatePreferencesResponse, error) {
+	user, err := s.findAtVersion(ctx, req.ID, req.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(user)
+	if err := s.repo.UpdatePreferences(ctx, user); err != nil {
+		if database.IsNotFound(err) {
+			return nil, s.concurrentWriteError(ctx, req.ID, err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgPreferencesResource), err)
+	}
+
+	return dtos.UpdatePreferencesResponseFromEntity(user), nil
+}
+
+// ExportUserData gathers the profile, uploaded files, sessions and status history of a user into a single archive.
+func (s *Service) ExportUserData(ctx context.Context, req *dtos.ExportUserDataRequest) (*dtos.ExportUserDataResponse, error) {
+	user, err := s.repo.FindByID(ctx, req.ID)
+	if err != nil {
+		return nil, errors.NotFound(err)
+	}
+
+	uploadedFiles, err := s.filesRepo.FindByOwner(ctx, user.ID)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgUserDataExportResource), err)
+	}
+
+	sessions, err := s.repo.FindSessions(ctx, user.ID)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgUserDataExportResource), err)
+	}
+
+	changes, err := s.repo.FindStatusChanges(ctx, user.ID)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgUserDataExportResource), err)
+	}
+
+	return dtos.ExportUserDataResponseFromEntities(user, uploadedFiles, sessions, changes), nil
+}
+
+// EraseUser anonymizes the personal data of a user and of the files they uploaded, removes their avatar and sessions,
+// and records the erasure in the status history, all in a single transaction. The user document itself is kept so
+// that references to it stay valid.
+func (s *Service) EraseUser(ctx context.Context, req *dtos.EraseUserRequest) error {
+	user, err := s.repo.FindByID(ctx, req.ID)
+	if err != nil {
+		return errors.NotFound(err)
+	}
+
+	if user.IsErased() {
+		return errors.UnprocessableEntityM(msgkey.ErrUserErased, nil)
+	}
+	if !user.CanTransitionTo(constants.UserStatusErased) {
+		return errors.UnprocessableEntityM(loc.L(msgkey.ErrInvalidStatusTransition, user.CurrentStatus(), constants.UserStatusErased), nil)
+	}
+
+	change := &entities.UserStatusChange{
+		ID:         idgenerator.GenerateID(),
+		UserID:     user.ID,
+		FromStatus: user.CurrentStatus(),
+		ToStatus:   constants.UserStatusErased,
+		Reason:     req.Reason,
+		ChangedBy:  req.ErasedBy,
+		CreatedAt:  time.Now(),
+	}
+
+	avatarURL, version := user.AvatarURL, user.Version
+	user.Anonymize(change.CreatedAt)
+	err = s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		user.Version = version // A retried attempt writes the version the previous one was rolled back from
+		if err := s.repo.Update(ctx, user); err != nil {
+			if database.IsNotFound(err) {
+				return s.concurrentWriteError(ctx, req.ID, err)
+			}
+			return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgUserResource), err)
+		}
+		if err := s.filesRepo.AnonymizeByOwner(ctx, user.ID); err != nil {
+			return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgFileResource), err)
+		}
+		if err := s.repo.DeleteSessions(ctx, user.ID); err != nil {
+			return errors.InternalServerError(err)
+		}
+		if err := s.repo.CreateStatusChange(ctx, change); err != nil {
+			return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgUserStatusHistoryResource), err)
+		}
+		return nil
+	})
+	if err != nil {
+		var httpError errors.HttpError
+		if goerrors.As(err, &httpError) {
+			return err
+		}
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgUserResource), err)
+	}
+
+	// The storage is not transactional, the avatar is only removed once nothing refers to it anymore
+	if avatarURL != "" {
+		s.removeAvatarFile(avatarURL)
+	}
+
+	return nil
+}
+
+// findAtVersion loads a user and checks that it is still at the version the client expects.
+func (s *Service) findAtVersion(ctx context.Context, id string, expectedVersion int64) (*entities.User, error) {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NotFound(err)
+	}
+
+	if !etag.Matches(expectedVersion, user.Version) {
+		return nil, errors.PreconditionFailed(user.Version)
+	}
+	return user, nil
+}
+
+// concurrentWriteError explains why a versioned write matched no user: either it was modified since it was read,
+// in which case its current version is reported, or it was deleted.
+func (s *Service) concurrentWriteError(ctx context.Context, id string, err error) error {
+	current, findErr := s.repo.FindByID(ctx, id)
+	if findErr != nil {
+		return errors.NotFound(err)
+	}
+	return errors.PreconditionFailed(current.Version)
+}
+
+// removeAvatarFile deletes an avatar file from the storage. Failures are only logged since the user no longer
+// references the file.
+func (s *Service) removeAvatarFile(avatarURL string) {
+	if err := s.fileService.DeleteFile(avatarURL); err != nil {
+		log.Printf("Error deleting avatar file %s: %v", avatarURL, err)
+	}
+}
+
+// changeStatus moves the user to the given status if the transition is allowed, records it in the status history
+// and notifies the user by email. When fromStatuses is given, the user's current status must be one of them.
+func (s *Service) changeStatus(ctx context.Context, req *dtos.ChangeUserStatusRequest, status string, fromStatuses ...string) (*dtos.ChangeUserStatusResponse, error) {
+	if req.ID == req.ChangedBy {
+		return nil, errors.ForbiddenM(msgkey.ErrUserStatusChangeNotAllowed, nil)
+	}
+
+	user, err := s.repo.FindByID(ctx, req.ID)
+	if err != nil {
+		return nil, errors.NotFound(err)
+	}
+
+	if !user.CanTransitionTo(status) || (len(fromStatuses) > 0 && !slices.Contains(fromStatuses, user.CurrentStatus())) {
+		return nil, errors.UnprocessableEntityM(loc.L(msgkey.ErrInvalidStatusTransition, user.CurrentStatus(), status), nil)
+	}
+
+	change := &entities.UserStatusChange{
+		ID:         idgenerator.GenerateID(),
+		UserID:     user.ID,
+		FromStatus: user.CurrentStatus(),
+		ToStatus:   status,
+		Reason:     req.Reason,
+		ChangedBy:  req.ChangedBy,
+		CreatedAt:  time.Now(),
+	}
+
+	version := user.Version
+	user.Status = status
+	err = s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		user.Version = version // A retried attempt writes the version the previous one was rolled back from
+		if err := s.repo.UpdateStatus(ctx, user); err != nil {
+			if database.IsNotFound(err) {
+				return s.concurrentWriteError(ctx, req.ID, err)
+			}
+			return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgUserResource), err)
+		}
+		if err := s.repo.CreateStatusChange(ctx, change); err != nil {
+			return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgUserStatusHistoryResource), err)
+		}
+		return nil
+	})
+	if err != nil {
+		var httpError errors.HttpError
+		if goerrors.As(err, &httpError) {
+			return nil, err
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgUserResource), err)
+	}
+
+	go func() {
+		if err := s.emailService.SendUserStatusChangedEmail(user.Email, user.FullName(), status, req.Reason, user.PreferredLanguage); err != nil {
+			log.Printf("Error sending status change email to user %s: %v", user.ID.Hex(), err)
+		}
+	}()
+
+	return dtos.ChangeUserStatusResponseFromEntity(user), nil
+}
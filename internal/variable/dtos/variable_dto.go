@@ -0,0 +1,84 @@
+package dtos
+
+import (
+	"company-name/entities"
+	"company-name/pkg/idgenerator"
+	"time"
+)
+
+type CreateVariableRequest struct {
+	Name        string `json:"name" validate:"required,max=100,excludesall=\"{}"` // Referred to as {{var "name"}}, hence no quotes nor braces
+	Value       string `json:"value" validate:"max=2000"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+func (req *CreateVariableRequest) ToEntity() *entities.Variable {
+	return &entities.Variable{
+		ID:          idgenerator.GenerateID(),
+		Name:        req.Name,
+		Value:       req.Value,
+		Description: req.Description,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+type UpdateVariableRequest struct {
+	Name        string `json:"-" validate:"required"`
+	Value       string `json:"value" validate:"max=2000"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// ApplyTo copies the updated value and description onto the stored variable.
+func (req *UpdateVariableRequest) ApplyTo(variable *entities.Variable) {
+	variable.Value = req.Value
+	variable.Description = req.Description
+	variable.UpdatedAt = time.Now()
+}
+
+type VariableRequest struct {
+	Name string `json:"-" validate:"required"`
+}
+
+type VariableDto struct {
+	Name        string    `json:"name"`
+	Value       string    `json:"value"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func VariableDtoFromEntity(entity *entities.Variable) *VariableDto {
+	return &VariableDto{
+		Name:        entity.Name,
+		Value:       entity.Value,
+		Description: entity.Description,
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}
+}
+
+// InLocation renders the timestamps of the variable in the given location.
+func (dto *VariableDto) InLocation(location *time.Location) {
+	dto.CreatedAt = dto.CreatedAt.In(location)
+	dto.UpdatedAt = dto.UpdatedAt.In(location)
+}
+
+type GetVariablesResponse struct {
+	Variables []VariableDto `json:"variables"`
+}
+
+func GetVariablesResponseFromEntity(variables []*entities.Variable) *GetVariablesResponse {
+	dtos := make([]VariableDto, 0, len(variables))
+	for _, variable := range variables {
+		dtos = append(dtos, *VariableDtoFromEntity(variable))
+	}
+	return &GetVariablesResponse{Variables: dtos}
+}
+
+// InLocation renders the timestamps of the variables in the given location.
+func (dto *GetVariablesResponse) InLocation(location *time.Location) {
+	for i := range dto.Variables {
+		dto.Variables[i].InLocation(location)
+	}
+}
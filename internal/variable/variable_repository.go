@@ -0,0 +1,68 @@
+package variable
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/database"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// IVariableRepository defines the interface for the variables inserted in the content of blocks
+type IVariableRepository interface {
+	Create(ctx context.Context, variable *entities.Variable) error
+	Update(ctx context.Context, variable *entities.Variable) error
+	Delete(ctx context.Context, name string) error
+	FindByName(ctx context.Context, name string) (*entities.Variable, error)
+	FindAll(ctx context.Context) ([]*entities.Variable, error)
+}
+
+type Repository struct {
+	db database.IDatabase
+}
+
+// NewVariableRepository initializes a new variable repository
+func NewVariableRepository(db database.IDatabase) IVariableRepository {
+	return &Repository{db: db}
+}
+
+// Create adds a new variable
+func (r *Repository) Create(ctx context.Context, variable *entities.Variable) error {
+	return r.db.Create(ctx, constants.DbVariablesCollection, variable)
+}
+
+// Update persists the value and description of an existing variable
+func (r *Repository) Update(ctx context.Context, variable *entities.Variable) error {
+	filter := bson.M{"_id": variable.ID}
+	update := bson.M{"$set": bson.M{
+		"value":       variable.Value,
+		"description": variable.Description,
+		"updated_at":  variable.UpdatedAt,
+	}}
+
+	return r.db.Update(ctx, constants.DbVariablesCollection, filter, update)
+}
+
+// Delete removes a variable by name
+func (r *Repository) Delete(ctx context.Context, name string) error {
+	return r.db.Delete(ctx, constants.DbVariablesCollection, bson.M{"name": name})
+}
+
+// FindByName retrieves a variable by its name
+func (r *Repository) FindByName(ctx context.Context, name string) (*entities.Variable, error) {
+	var variable entities.Variable
+	if err := r.db.FindOne(ctx, constants.DbVariablesCollection, bson.M{"name": name}, &variable); err != nil {
+		return nil, err
+	}
+	return &variable, nil
+}
+
+// FindAll retrieves every variable, by name
+func (r *Repository) FindAll(ctx context.Context) ([]*entities.Variable, error) {
+	variables := []*entities.Variable{}
+	if err := r.db.FindWithPagination(ctx, constants.DbVariablesCollection, bson.M{}, "name", constants.SortAsc, 0, 0, &variables); err != nil {
+		return nil, err
+	}
+	return variables, nil
+}
@@ -0,0 +1,111 @@
+package variable
+
+import (
+	"company-name/constants/msgkey"
+	"company-name/entities"
+	"company-name/internal/variable/dtos"
+	"company-name/pkg/database"
+	"company-name/pkg/errors"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/validators"
+	"context"
+)
+
+// IVariablesService manages the variables shared by the content blocks. Blocks referring to a variable that does not
+// exist, e.g. once deleted, show nothing in its place.
+type IVariablesService interface {
+	CreateVariable(ctx context.Context, req *dtos.CreateVariableRequest) (*dtos.VariableDto, error)
+	UpdateVariable(ctx context.Context, req *dtos.UpdateVariableRequest) (*dtos.VariableDto, error)
+	DeleteVariable(ctx context.Context, req *dtos.VariableRequest) error
+	GetVariable(ctx context.Context, req *dtos.VariableRequest) (*dtos.VariableDto, error)
+	GetVariables(ctx context.Context) (*dtos.GetVariablesResponse, error)
+}
+
+type Service struct {
+	repo      IVariableRepository
+	validator validators.IValidator
+}
+
+// NewVariablesService initializes a new variables service
+func NewVariablesService(repo IVariableRepository, validator validators.IValidator) IVariablesService {
+	return &Service{
+		repo:      repo,
+		validator: validator,
+	}
+}
+
+// CreateVariable defines a new variable, whose name must not be used yet.
+func (s *Service) CreateVariable(ctx context.Context, req *dtos.CreateVariableRequest) (*dtos.VariableDto, error) {
+	variable := req.ToEntity()
+	if err := s.validator.ValidateStruct(variable); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, variable); err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, errors.Conflict(err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceCreated, msgkey.MsgVariableResource), err)
+	}
+
+	return dtos.VariableDtoFromEntity(variable), nil
+}
+
+// UpdateVariable replaces the value and description of a variable. The blocks referring to it show the new value on
+// their next read.
+func (s *Service) UpdateVariable(ctx context.Context, req *dtos.UpdateVariableRequest) (*dtos.VariableDto, error) {
+	variable, err := s.find(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(variable)
+	if err := s.repo.Update(ctx, variable); err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceUpdated, msgkey.MsgVariableResource), err)
+	}
+
+	return dtos.VariableDtoFromEntity(variable), nil
+}
+
+// DeleteVariable removes a variable by name.
+func (s *Service) DeleteVariable(ctx context.Context, req *dtos.VariableRequest) error {
+	if err := s.repo.Delete(ctx, req.Name); err != nil {
+		if database.IsNotFound(err) {
+			return errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgVariableResource), err)
+		}
+		return errors.InternalServerErrorM(loc.L(msgkey.ErrResourceDeleted, msgkey.MsgVariableResource), err)
+	}
+
+	return nil
+}
+
+// GetVariable returns a variable by name.
+func (s *Service) GetVariable(ctx context.Context, req *dtos.VariableRequest) (*dtos.VariableDto, error) {
+	variable, err := s.find(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return dtos.VariableDtoFromEntity(variable), nil
+}
+
+// GetVariables returns every variable, by name.
+func (s *Service) GetVariables(ctx context.Context) (*dtos.GetVariablesResponse, error) {
+	variables, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgVariableResource), err)
+	}
+
+	return dtos.GetVariablesResponseFromEntity(variables), nil
+}
+
+func (s *Service) find(ctx context.Context, name string) (*entities.Variable, error) {
+	variable, err := s.repo.FindByName(ctx, name)
+	if err != nil {
+		if database.IsNotFound(err) {
+			return nil, errors.NotFoundM(loc.L(msgkey.ErrResourceNotFound, msgkey.MsgVariableResource), err)
+		}
+		return nil, errors.InternalServerErrorM(loc.L(msgkey.ErrResourceFetched, msgkey.MsgVariableResource), err)
+	}
+	return variable, nil
+}
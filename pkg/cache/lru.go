@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU is a fixed-size cache safe for concurrent use, evicting the least recently used entry when full. Entries also
+// expire after a time to live, which bounds how stale they get when the data changes without the cache being told,
+// e.g. when written by another process.
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List // Most recently used first
+	entries  map[K]*list.Element
+}
+
+type lruEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// NewLRU initializes a cache holding up to capacity entries, each for at most ttl. A capacity below 1 disables the
+// cache and a ttl of 0 never expires entries.
+func NewLRU[K comparable, V any](capacity int, ttl time.Duration) *LRU[K, V] {
+	return &LRU[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  map[K]*list.Element{},
+	}
+}
+
+// Get returns the value cached under key, if any and not expired, and marks it as recently used.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	element, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+
+	entry := element.Value.(*lruEntry[K, V])
+	if c.ttl > 0 && time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return zero, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Add caches value under key, replacing the value cached before, and evicts the least recently used entry when full.
+func (c *LRU[K, V]) Add(key K, value V) {
+	if c.capacity < 1 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &lruEntry[K, V]{key: key, value: value, expiresAt: time.Now().Add(c.ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	if c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// RemoveFunc removes the entries whose key and value satisfy match.
+func (c *LRU[K, V]) RemoveFunc(match func(key K, value V) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.entries {
+		if match(key, element.Value.(*lruEntry[K, V]).value) {
+			c.remove(element)
+		}
+	}
+}
+
+func (c *LRU[K, V]) remove(element *list.Element) {
+	entry := element.Value.(*lruEntry[K, V])
+	delete(c.entries, entry.key)
+	c.order.Remove(element)
+}
@@ -3,6 +3,8 @@ package database
 import (
 	"company-name/constants"
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -12,12 +14,16 @@ import (
 
 const DatabaseTimeout = 5 * time.Second
 
+// ErrDuplicateKey is returned by writes violating a unique index.
+var ErrDuplicateKey = errors.New("duplicate key")
+
 type IDatabase interface {
 	GetDB() *mongo.Database
 	WithTransaction(ctx context.Context, function func(sessCtx mongo.SessionContext) error) error
 	Create(ctx context.Context, collection string, doc interface{}) error
 	CreateInBatches(ctx context.Context, collection string, docs []interface{}) error
 	Update(ctx context.Context, collection string, filter, update interface{}) error
+	Upsert(ctx context.Context, collection string, filter, update interface{}) (bool, error)
 	Delete(ctx context.Context, collection string, filter interface{}) error
 	DeleteAll(ctx context.Context, collection string, filter interface{}) error
 	SoftDelete(ctx context.Context, collection string, filter interface{}) error
@@ -25,7 +31,11 @@ type IDatabase interface {
 	FindOne(ctx context.Context, collection string, filter, result interface{}) error
 	Find(ctx context.Context, collection string, filter, result interface{}) error
 	FindWithPagination(ctx context.Context, collection string, filter interface{}, sortField, sortOrder string, offset, limit int64, result interface{}) error
+	FindByTextScore(ctx context.Context, collection string, filter interface{}, offset, limit int64, result interface{}) error
 	Count(ctx context.Context, collection string, filter interface{}) (int64, error)
+	Aggregate(ctx context.Context, collection string, filter interface{}, stages []bson.M, result interface{}) error
+	EnsureIndexes(ctx context.Context, definitions []IndexDefinition) error
+	IndexDrift(ctx context.Context, definitions []IndexDefinition) ([]IndexDrift, error)
 }
 
 type Database struct {
@@ -81,7 +91,7 @@ func (d *Database) Create(ctx context.Context, collection string, doc interface{
 	defer cancel()
 
 	_, err := d.database.Collection(collection).InsertOne(ctx, doc)
-	return err
+	return translateWriteError(err)
 }
 
 func (d *Database) CreateInBatches(ctx context.Context, collection string, docs []interface{}) error {
@@ -89,23 +99,48 @@ func (d *Database) CreateInBatches(ctx context.Context, collection string, docs
 	defer cancel()
 
 	_, err := d.database.Collection(collection).InsertMany(ctx, docs)
-	return err
+	return translateWriteError(err)
 }
 
 func (d *Database) Update(ctx context.Context, collection string, filter, update interface{}) error {
 	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
 	defer cancel()
 
-	_, err := d.database.Collection(collection).UpdateOne(ctx, filter, update)
-	return err
+	updateResult, err := d.database.Collection(collection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return translateWriteError(err)
+	}
+	if updateResult.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Upsert updates the document matching the filter, or inserts one built from the equality conditions of the filter and
+// the update when none matches. It reports whether a document was inserted.
+func (d *Database) Upsert(ctx context.Context, collection string, filter, update interface{}) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
+	defer cancel()
+
+	updateResult, err := d.database.Collection(collection).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, translateWriteError(err)
+	}
+	return updateResult.UpsertedCount > 0, nil
 }
 
 func (d *Database) Delete(ctx context.Context, collection string, filter interface{}) error {
 	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
 	defer cancel()
 
-	_, err := d.database.Collection(collection).DeleteOne(ctx, filter)
-	return err
+	deleteResult, err := d.database.Collection(collection).DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if deleteResult.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 
 func (d *Database) DeleteAll(ctx context.Context, collection string, filter interface{}) error {
@@ -185,6 +220,29 @@ func (d *Database) FindWithPagination(ctx context.Context, collection string, fi
 	return err
 }
 
+// FindByTextScore runs a filter holding a $text condition and returns the matches by decreasing relevance, with the
+// relevance itself in the "score" field of every document.
+func (d *Database) FindByTextScore(ctx context.Context, collection string, filter interface{}, offset, limit int64, result interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
+	defer cancel()
+
+	score := bson.M{"score": bson.M{"$meta": "textScore"}}
+	findOptions := options.Find().
+		SetProjection(score).
+		SetSort(score).
+		SetSkip(offset).
+		SetLimit(limit)
+
+	cursor, err := d.database.Collection(collection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, result)
+	return err
+}
+
 func (d *Database) Count(ctx context.Context, collection string, filter interface{}) (int64, error) {
 	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
 	defer cancel()
@@ -192,3 +250,46 @@ func (d *Database) Count(ctx context.Context, collection string, filter interfac
 	count, err := d.database.Collection(collection).CountDocuments(ctx, filter)
 	return count, err
 }
+
+// Aggregate runs the given stages on the documents matching the filter.
+func (d *Database) Aggregate(ctx context.Context, collection string, filter interface{}, stages []bson.M, result interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
+	defer cancel()
+
+	pipeline := append([]bson.M{{"$match": filter}}, stages...)
+	cursor, err := d.database.Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, result)
+	return err
+}
+
+// VersionFilter matches documents at the given version. Documents written before versioning was introduced have no
+// version field and are treated as version 0.
+func VersionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// IsNotFound reports whether an operation failed because no document matched its filter.
+func IsNotFound(err error) bool {
+	return errors.Is(err, mongo.ErrNoDocuments)
+}
+
+// IsDuplicateKey reports whether a write failed because it violated a unique index.
+func IsDuplicateKey(err error) bool {
+	return errors.Is(err, ErrDuplicateKey)
+}
+
+// translateWriteError wraps unique index violations into ErrDuplicateKey so callers don't depend on the driver.
+func translateWriteError(err error) error {
+	if err != nil && mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %v", ErrDuplicateKey, err)
+	}
+	return err
+}
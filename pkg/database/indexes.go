@@ -0,0 +1,288 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	IndexDriftMissing    = "missing"
+	IndexDriftChanged    = "changed"
+	IndexDriftUnexpected = "unexpected"
+)
+
+// TextIndexLanguage disables stemming and stop words on text indexes: they only exist for some languages, Arabic not
+// among them, and would otherwise apply English rules to names. Case and diacritics are folded regardless.
+const TextIndexLanguage = "none"
+
+// IndexDefinition describes an index the application expects to exist on a collection.
+type IndexDefinition struct {
+	Collection string
+	Name       string
+	Keys       bson.D
+	Unique     bool
+	Weights    bson.D // Relevance weights of the fields of a text index, fields left out weigh 1
+}
+
+// IndexDrift describes a difference between the declared indexes and the ones existing in the database.
+type IndexDrift struct {
+	Collection string
+	Name       string
+	Status     string
+	Detail     string
+}
+
+// IndexesFromStruct reads the `index:"name[,unique][,desc][,text][,weight=N]"` tags of a struct, including nested
+// structs, and returns one definition per index name. Fields sharing a name form a compound index in declaration order,
+// and nested fields are addressed with dotted paths built from their bson names. A field belonging to several indexes
+// lists them separated by semicolons.
+func IndexesFromStruct(collection string, model interface{}) []IndexDefinition {
+	var definitions []IndexDefinition
+	positions := map[string]int{}
+
+	collectIndexFields(reflect.TypeOf(model), "", func(name, field string, tagOptions []string) {
+		position, exists := positions[name]
+		if !exists {
+			position = len(definitions)
+			positions[name] = position
+			definitions = append(definitions, IndexDefinition{Collection: collection, Name: name})
+		}
+
+		var order interface{} = 1
+		for _, option := range tagOptions {
+			switch option {
+			case "unique":
+				definitions[position].Unique = true
+			case "desc":
+				order = -1
+			case "text":
+				order = "text"
+			}
+
+			if weight, ok := strings.CutPrefix(option, "weight="); ok {
+				if value, err := strconv.Atoi(weight); err == nil {
+					definitions[position].Weights = append(definitions[position].Weights, bson.E{Key: field, Value: value})
+				}
+			}
+		}
+		definitions[position].Keys = append(definitions[position].Keys, bson.E{Key: field, Value: order})
+	})
+
+	return definitions
+}
+
+func collectIndexFields(t reflect.Type, prefix string, collect func(name, field string, options []string)) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		bsonName, _, _ := strings.Cut(field.Tag.Get("bson"), ",")
+		if bsonName == "-" {
+			continue
+		}
+		if bsonName == "" {
+			bsonName = strings.ToLower(field.Name)
+		}
+		path := prefix + bsonName
+
+		if tag, ok := field.Tag.Lookup("index"); ok {
+			for _, index := range strings.Split(tag, ";") {
+				parts := strings.Split(index, ",")
+				collect(parts[0], path, parts[1:])
+			}
+		}
+
+		if isNestedDocument(field.Type) {
+			collectIndexFields(field.Type, path+".", collect)
+		}
+	}
+}
+
+func isNestedDocument(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct &&
+		t != reflect.TypeOf(time.Time{}) &&
+		t != reflect.TypeOf(primitive.ObjectID{})
+}
+
+// EnsureIndexes creates the declared indexes that do not exist yet. An index that cannot be created, e.g. a unique
+// index over duplicated values, does not prevent the others from being created; all failures are returned together.
+func (d *Database) EnsureIndexes(ctx context.Context, definitions []IndexDefinition) error {
+	var errs []error
+	for _, definition := range definitions {
+		indexOptions := options.Index().SetName(definition.Name).SetUnique(definition.Unique)
+		if isTextIndex(definition.Keys) {
+			indexOptions.SetDefaultLanguage(TextIndexLanguage)
+			if len(definition.Weights) > 0 {
+				indexOptions.SetWeights(definition.Weights)
+			}
+		}
+		model := mongo.IndexModel{
+			Keys:    definition.Keys,
+			Options: indexOptions,
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
+		_, err := d.database.Collection(definition.Collection).Indexes().CreateOne(ctx, model)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("index %s.%s: %w", definition.Collection, definition.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// IndexDrift compares the declared indexes with the ones existing on the same collections.
+func (d *Database) IndexDrift(ctx context.Context, definitions []IndexDefinition) ([]IndexDrift, error) {
+	declared := map[string]map[string]IndexDefinition{}
+	var collections []string
+	for _, definition := range definitions {
+		if _, ok := declared[definition.Collection]; !ok {
+			declared[definition.Collection] = map[string]IndexDefinition{}
+			collections = append(collections, definition.Collection)
+		}
+		declared[definition.Collection][definition.Name] = definition
+	}
+
+	var drifts []IndexDrift
+	for _, collection := range collections {
+		actual, err := d.listIndexes(ctx, collection)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, definition := range definitions {
+			if definition.Collection != collection {
+				continue
+			}
+
+			existing, ok := actual[definition.Name]
+			switch {
+			case !ok:
+				drifts = append(drifts, IndexDrift{collection, definition.Name, IndexDriftMissing, describeKeys(definition)})
+			case describeKeys(definition) != describeKeys(existing) || definition.Unique != existing.Unique:
+				detail := fmt.Sprintf("declared %s unique=%t, found %s unique=%t",
+					describeKeys(definition), definition.Unique, describeKeys(existing), existing.Unique)
+				drifts = append(drifts, IndexDrift{collection, definition.Name, IndexDriftChanged, detail})
+			}
+		}
+
+		for name, existing := range actual {
+			if _, ok := declared[collection][name]; !ok && name != "_id_" {
+				drifts = append(drifts, IndexDrift{collection, name, IndexDriftUnexpected, describeKeys(existing)})
+			}
+		}
+	}
+
+	return drifts, nil
+}
+
+func (d *Database) listIndexes(ctx context.Context, collection string) (map[string]IndexDefinition, error) {
+	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
+	defer cancel()
+
+	cursor, err := d.database.Collection(collection).Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var specs []struct {
+		Name    string `bson:"name"`
+		Key     bson.D `bson:"key"`
+		Unique  bool   `bson:"unique"`
+		Weights bson.D `bson:"weights"`
+	}
+	if err := cursor.All(ctx, &specs); err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[string]IndexDefinition, len(specs))
+	for _, spec := range specs {
+		keys := spec.Key
+		if len(spec.Weights) > 0 {
+			// Text indexes are stored as {_fts: "text", _ftsx: 1}, the indexed fields only appear in the weights
+			keys = slices.DeleteFunc(slices.Clone(keys), func(key bson.E) bool {
+				return key.Key == "_fts" || key.Key == "_ftsx"
+			})
+			for _, weight := range spec.Weights {
+				keys = append(keys, bson.E{Key: weight.Key, Value: "text"})
+			}
+		}
+		indexes[spec.Name] = IndexDefinition{
+			Collection: collection,
+			Name:       spec.Name,
+			Keys:       keys,
+			Unique:     spec.Unique,
+			Weights:    spec.Weights,
+		}
+	}
+	return indexes, nil
+}
+
+func isTextIndex(keys bson.D) bool {
+	return slices.ContainsFunc(keys, func(key bson.E) bool { return key.Value == "text" })
+}
+
+// describeKeys renders index keys as "{field: order, ...}", normalizing numeric orders read back from the database.
+// Text fields come last in alphabetical order with their weight, since the database does not keep their order.
+func describeKeys(definition IndexDefinition) string {
+	weights := map[string]int{}
+	for _, weight := range definition.Weights {
+		if value, ok := normalizeNumber(weight.Value).(int); ok {
+			weights[weight.Key] = value
+		}
+	}
+
+	parts := make([]string, 0, len(definition.Keys))
+	var textParts []string
+	for _, key := range definition.Keys {
+		if key.Value == "text" {
+			weight, ok := weights[key.Key]
+			if !ok {
+				weight = 1
+			}
+			textParts = append(textParts, fmt.Sprintf("%s: text(%d)", key.Key, weight))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s: %v", key.Key, normalizeNumber(key.Value)))
+	}
+	slices.Sort(textParts)
+
+	return "{" + strings.Join(append(parts, textParts...), ", ") + "}"
+}
+
+func normalizeNumber(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return value
+}
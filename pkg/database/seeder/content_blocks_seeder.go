@@ -1,5 +1,110 @@
 package seeder
 
-// home - about
-// home - conatct
-// home - footer
+import (
+	"bytes"
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/localization"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BlockFixture is the variant of a content block, identified by its page, section and locale. The content of a typed
+// block may be written as structured data, which is stored as JSON.
+type BlockFixture struct {
+	Page      string      `json:"page" yaml:"page"`
+	Section   string      `json:"section" yaml:"section"`
+	Locale    string      `json:"locale" yaml:"locale"` // Default language when empty
+	Format    string      `json:"format" yaml:"format"` // HTML when empty, ignored for typed blocks
+	Type      string      `json:"type" yaml:"type"`
+	Content   interface{} `json:"content" yaml:"content"`
+	Published bool        `json:"published" yaml:"published"` // Publishes the content, otherwise a new block is a draft and an existing one keeps its published content
+}
+
+// seedBlock creates the block or replaces its content, as a new version. Blocks without revisions get their baseline
+// revision the next time they are written through the API.
+func (s *Seeder) seedBlock(ctx context.Context, block BlockFixture) error {
+	if block.Page == "" || block.Section == "" || block.Content == nil {
+		return fmt.Errorf("page, section and content are required")
+	}
+
+	content, format, err := blockContent(block)
+	if err != nil {
+		return err
+	}
+
+	key := entities.NewBlockKey(block.Page, block.Section, block.Locale)
+	filter := bson.M{"key.page": key.Page, "key.section": key.Section, "key.locale": key.Locale}
+	if key.Locale == localization.DefaultLang {
+		// Blocks written before variants existed have no locale and are the variant in the default language
+		filter["key.locale"] = bson.M{"$in": bson.A{key.Locale, nil}}
+	}
+
+	now := time.Now()
+	set := bson.M{
+		"key":        key,
+		"content":    content,
+		"format":     format,
+		"updated_at": now,
+	}
+	setOnInsert := bson.M{
+		"_id":        primitive.NewObjectID(),
+		"created_at": now,
+	}
+	if block.Type != "" {
+		set["type"] = block.Type
+	}
+	if block.Published {
+		set["status"] = constants.BlockStatusPublished
+		set["published_content"] = content
+		set["published_format"] = format
+		set["published_at"] = now
+	} else {
+		setOnInsert["status"] = constants.BlockStatusDraft
+	}
+
+	update := bson.M{
+		"$set":         set,
+		"$setOnInsert": setOnInsert,
+		"$inc":         bson.M{"version": 1},
+	}
+
+	_, err = s.db.Upsert(ctx, constants.DbContentBlocksCollection, filter, update)
+	return err
+}
+
+// blockContent returns the content of a block as stored, with its format. The content of typed blocks is indented
+// JSON, like the content written through the API.
+func blockContent(block BlockFixture) (string, string, error) {
+	if block.Type == "" {
+		text, ok := block.Content.(string)
+		if !ok {
+			return "", "", fmt.Errorf("the content of an untyped block must be text")
+		}
+		format := block.Format
+		if format == "" {
+			format = constants.BlockFormatHTML
+		}
+		return text, format, nil
+	}
+
+	data, ok := block.Content.(string)
+	if !ok {
+		encoded, err := json.Marshal(block.Content)
+		if err != nil {
+			return "", "", err
+		}
+		data = string(encoded)
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(data), "", "  "); err != nil {
+		return "", "", err
+	}
+	return indented.String(), constants.BlockFormatJSON, nil
+}
@@ -0,0 +1,54 @@
+package seeder
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PageFixture is the metadata of a page, identified by its slug.
+type PageFixture struct {
+	Slug            string   `json:"slug" yaml:"slug"`
+	Title           string   `json:"title" yaml:"title"`
+	MetaDescription string   `json:"meta_description" yaml:"meta_description"`
+	OGImage         string   `json:"og_image" yaml:"og_image"`
+	Sections        []string `json:"sections" yaml:"sections"`
+	Tags            []string `json:"tags" yaml:"tags"`
+}
+
+// seedPage creates the page or replaces its metadata.
+func (s *Seeder) seedPage(ctx context.Context, page PageFixture) error {
+	if page.Slug == "" || page.Title == "" {
+		return fmt.Errorf("slug and title are required")
+	}
+
+	sections := page.Sections
+	if sections == nil {
+		sections = []string{}
+	}
+
+	now := time.Now()
+	filter := bson.M{"slug": page.Slug}
+	update := bson.M{
+		"$set": bson.M{
+			"title":            page.Title,
+			"meta_description": page.MetaDescription,
+			"og_image":         page.OGImage,
+			"sections":         sections,
+			"tags":             entities.NormalizeTags(page.Tags),
+			"updated_at":       now,
+		},
+		"$setOnInsert": bson.M{
+			"_id":        primitive.NewObjectID(),
+			"created_at": now,
+		},
+	}
+
+	_, err := s.db.Upsert(ctx, constants.DbPagesCollection, filter, update)
+	return err
+}
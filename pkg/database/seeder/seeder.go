@@ -0,0 +1,217 @@
+package seeder
+
+import (
+	"company-name/constants"
+	"company-name/entities"
+	"company-name/pkg/database"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gopkg.in/yaml.v3"
+)
+
+// Outcomes of a fixture, as reported by Seed.
+const (
+	StatusApplied          = "applied"
+	StatusPending          = "pending" // Would be applied, in a dry run
+	StatusAlreadyApplied   = "already applied"
+	StatusOtherEnvironment = "other environment"
+)
+
+// Fixture is a file of seed data, written in YAML or JSON. A fixture is applied once per version: changing its data
+// requires a new version for the change to be applied to the databases that already have it.
+type Fixture struct {
+	Name         string         `json:"name" yaml:"name"`
+	Version      int            `json:"version" yaml:"version"`
+	Environments []string       `json:"environments" yaml:"environments"` // Environments the fixture is applied to, every one when empty
+	Tenant       string         `json:"tenant" yaml:"tenant"`             // Slug of the organization the data belongs to, the platform itself when empty
+	Admin        *AdminFixture  `json:"admin" yaml:"admin"`
+	Pages        []PageFixture  `json:"pages" yaml:"pages"`
+	Blocks       []BlockFixture `json:"blocks" yaml:"blocks"`
+
+	path     string
+	checksum string
+}
+
+// Result is the outcome of a fixture.
+type Result struct {
+	Fixture string
+	Version int
+	Path    string
+	Status  string
+	Detail  string
+}
+
+// LoadFixtures reads the .yaml, .yml and .json fixtures of a directory, in file name order. Every fixture must have a
+// name, unique within the directory, and a positive version.
+func LoadFixtures(dir string) ([]*Fixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []*Fixture
+	names := map[string]string{}
+	for _, entry := range entries {
+		extension := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || !slices.Contains([]string{".yaml", ".yml", ".json"}, extension) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		fixture, err := loadFixture(path, extension)
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", path, err)
+		}
+		if previous, ok := names[fixture.Name]; ok {
+			return nil, fmt.Errorf("fixture %s: name %q already used by %s", path, fixture.Name, previous)
+		}
+		names[fixture.Name] = path
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+func loadFixture(path, extension string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture Fixture
+	if extension == ".json" {
+		err = json.Unmarshal(data, &fixture)
+	} else {
+		err = yaml.Unmarshal(data, &fixture)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if fixture.Name == "" {
+		return nil, fmt.Errorf("missing name")
+	}
+	if fixture.Version < 1 {
+		return nil, fmt.Errorf("version must be at least 1")
+	}
+
+	checksum := sha256.Sum256(data)
+	fixture.path = path
+	fixture.checksum = hex.EncodeToString(checksum[:])
+	return &fixture, nil
+}
+
+// Seeder applies fixtures to the database of an environment. Records are upserted by their natural key, so that
+// applying a fixture again, e.g. after a failure midway, updates what the previous attempt wrote instead of duplicating
+// it. The data of a fixture is trusted: it is written as is, without the checks made on content written through the API.
+type Seeder struct {
+	db          database.IDatabase
+	environment string
+}
+
+// NewSeeder initializes a seeder writing to db, which is expected to be scoped to tenants like the API's.
+func NewSeeder(db database.IDatabase, environment string) *Seeder {
+	return &Seeder{db: db, environment: environment}
+}
+
+// Seed applies, in order, the fixtures meant for the environment whose version was not applied yet, and records every
+// version applied. In a dry run nothing is written and those fixtures are reported as pending. Seeding stops at the
+// first fixture that fails.
+func (s *Seeder) Seed(ctx context.Context, fixtures []*Fixture, dryRun bool) ([]Result, error) {
+	results := make([]Result, 0, len(fixtures))
+	for _, fixture := range fixtures {
+		result := Result{Fixture: fixture.Name, Version: fixture.Version, Path: fixture.path}
+
+		if len(fixture.Environments) > 0 && !slices.Contains(fixture.Environments, s.environment) {
+			result.Status = StatusOtherEnvironment
+			results = append(results, result)
+			continue
+		}
+
+		applied, err := s.lastApplied(ctx, fixture.Name)
+		if err != nil {
+			return results, err
+		}
+		if applied != nil && applied.Version >= fixture.Version {
+			result.Status = StatusAlreadyApplied
+			if applied.Version == fixture.Version && applied.Checksum != fixture.checksum {
+				result.Detail = "changed since it was applied, its version must be increased for the change to apply"
+			}
+			results = append(results, result)
+			continue
+		}
+
+		if dryRun {
+			result.Status = StatusPending
+			results = append(results, result)
+			continue
+		}
+
+		if err := s.apply(ctx, fixture); err != nil {
+			return results, fmt.Errorf("fixture %s: %w", fixture.Name, err)
+		}
+		result.Status = StatusApplied
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// apply writes the data of a fixture, then records its version.
+func (s *Seeder) apply(ctx context.Context, fixture *Fixture) error {
+	if fixture.Tenant != "" {
+		var organization entities.Organization
+		if err := s.db.FindOne(ctx, constants.DbOrganizationsCollection, bson.M{"slug": fixture.Tenant}, &organization); err != nil {
+			return fmt.Errorf("organization %s: %w", fixture.Tenant, err)
+		}
+		ctx = database.WithTenant(ctx, organization.ID)
+	}
+
+	if fixture.Admin != nil {
+		if err := s.seedAdmin(ctx, fixture.Admin); err != nil {
+			return fmt.Errorf("admin %s: %w", fixture.Admin.Email, err)
+		}
+	}
+	for _, page := range fixture.Pages {
+		if err := s.seedPage(ctx, page); err != nil {
+			return fmt.Errorf("page %s: %w", page.Slug, err)
+		}
+	}
+	for _, block := range fixture.Blocks {
+		if err := s.seedBlock(ctx, block); err != nil {
+			return fmt.Errorf("block %s/%s: %w", block.Page, block.Section, err)
+		}
+	}
+
+	return s.db.Create(ctx, constants.DbSeedHistoryCollection, &entities.SeedHistory{
+		ID:          primitive.NewObjectID(),
+		Fixture:     fixture.Name,
+		Version:     fixture.Version,
+		Checksum:    fixture.checksum,
+		Environment: s.environment,
+		AppliedAt:   time.Now(),
+	})
+}
+
+// lastApplied returns the latest version of a fixture applied, nil when none was.
+func (s *Seeder) lastApplied(ctx context.Context, fixture string) (*entities.SeedHistory, error) {
+	var history []*entities.SeedHistory
+	filter := bson.M{"fixture": fixture}
+
+	if err := s.db.FindWithPagination(ctx, constants.DbSeedHistoryCollection, filter, "version", constants.SortDesc, 0, 1, &history); err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, nil
+	}
+	return history[0], nil
+}
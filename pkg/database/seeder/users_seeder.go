@@ -0,0 +1,72 @@
+package seeder
+
+import (
+	"company-name/constants"
+	"company-name/pkg/hasher"
+	"company-name/pkg/localization"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// samplePassword is the placeholder the admin password was once documented with, refused so that no deployment keeps
+// it.
+const samplePassword = "ChangeMe123!"
+
+// AdminFixture is the initial administrator of the platform, or of the organization of the fixture. Its password is
+// read from the environment variable named by PasswordEnv, or given as Password in development fixtures.
+type AdminFixture struct {
+	Email       string `json:"email" yaml:"email"`
+	FirstName   string `json:"first_name" yaml:"first_name"`
+	LastName    string `json:"last_name" yaml:"last_name"`
+	PhoneNumber string `json:"phone_number" yaml:"phone_number"`
+	Password    string `json:"password" yaml:"password"`
+	PasswordEnv string `json:"password_env" yaml:"password_env"`
+}
+
+// seedAdmin creates the administrator unless a user with that email exists, which is then left untouched so that its
+// password and role are never reset.
+func (s *Seeder) seedAdmin(ctx context.Context, admin *AdminFixture) error {
+	if admin.Email == "" || admin.FirstName == "" || admin.LastName == "" {
+		return fmt.Errorf("email, first name and last name are required")
+	}
+
+	password := admin.Password
+	if admin.PasswordEnv != "" {
+		password = os.Getenv(admin.PasswordEnv)
+	}
+	if password == "" {
+		return fmt.Errorf("no password given, set %s", admin.PasswordEnv)
+	}
+	if password == samplePassword {
+		return fmt.Errorf("the sample password cannot be used, set %s to another one", admin.PasswordEnv)
+	}
+
+	hashedPassword, err := hasher.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	filter := bson.M{"email": admin.Email}
+	update := bson.M{"$setOnInsert": bson.M{
+		"_id":                primitive.NewObjectID(),
+		"hashed_password":    hashedPassword,
+		"first_name":         admin.FirstName,
+		"last_name":          admin.LastName,
+		"phone_number":       admin.PhoneNumber,
+		"preferred_language": localization.DefaultLang,
+		"role":               constants.UserRoleAdmin,
+		"status":             constants.UserStatusActivated,
+		"version":            1,
+		"created_at":         now,
+		"updated_at":         now,
+	}}
+
+	_, err = s.db.Upsert(ctx, constants.DbUsersCollection, filter, update)
+	return err
+}
@@ -0,0 +1,166 @@
+package database
+
+import (
+	"company-name/constants"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TenantField is the field holding the organization a tenant-scoped document belongs to.
+const TenantField = "tenant_id"
+
+type unscopedKey struct{}
+
+// TenantOwned is implemented by the entities stored in tenant-scoped collections, so that the tenant of the request
+// can be stamped on them when they are created.
+type TenantOwned interface {
+	SetTenantID(tenantID *primitive.ObjectID)
+}
+
+// WithTenant returns a context scoped to the given tenant, for work done outside an HTTP request.
+func WithTenant(ctx context.Context, tenantID primitive.ObjectID) context.Context {
+	return context.WithValue(ctx, constants.ContextTenantIDKey, tenantID)
+}
+
+// TenantFromContext returns the tenant the context is scoped to. Requests handled by gin carry it in the gin context
+// under the same key.
+func TenantFromContext(ctx context.Context) (primitive.ObjectID, bool) {
+	tenantID, ok := ctx.Value(constants.ContextTenantIDKey).(primitive.ObjectID)
+	return tenantID, ok
+}
+
+// WithoutTenantScope returns a context whose operations reach every tenant. It is meant for the few lookups that must
+// cross tenants, such as finding the user of a token before the tenant is known.
+func WithoutTenantScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+func isUnscoped(ctx context.Context) bool {
+	unscoped, _ := ctx.Value(unscopedKey{}).(bool)
+	return unscoped
+}
+
+// TenantScopedDatabase restricts the operations on some collections to the documents of the tenant found in the
+// context, and stamps that tenant on the documents it creates. Without a tenant, operations reach the documents that
+// belong to no tenant, so that data never leaks from a tenant to a request that does not name it. The operations that
+// do not touch documents, such as index management, are passed through.
+type TenantScopedDatabase struct {
+	IDatabase
+	collections map[string]bool
+}
+
+// NewTenantScopedDatabase wraps db so that the given collections are scoped to the tenant of every operation.
+func NewTenantScopedDatabase(db IDatabase, collections ...string) IDatabase {
+	scoped := make(map[string]bool, len(collections))
+	for _, collection := range collections {
+		scoped[collection] = true
+	}
+	return &TenantScopedDatabase{IDatabase: db, collections: scoped}
+}
+
+func (d *TenantScopedDatabase) Create(ctx context.Context, collection string, doc interface{}) error {
+	d.stamp(ctx, collection, doc)
+	return d.IDatabase.Create(ctx, collection, doc)
+}
+
+func (d *TenantScopedDatabase) CreateInBatches(ctx context.Context, collection string, docs []interface{}) error {
+	for _, doc := range docs {
+		d.stamp(ctx, collection, doc)
+	}
+	return d.IDatabase.CreateInBatches(ctx, collection, docs)
+}
+
+func (d *TenantScopedDatabase) Update(ctx context.Context, collection string, filter, update interface{}) error {
+	return d.IDatabase.Update(ctx, collection, d.scope(ctx, collection, filter), update)
+}
+
+// Upsert scopes the filter like Update. The tenant condition being an equality, an inserted document belongs to the
+// tenant of the context.
+func (d *TenantScopedDatabase) Upsert(ctx context.Context, collection string, filter, update interface{}) (bool, error) {
+	return d.IDatabase.Upsert(ctx, collection, d.scope(ctx, collection, filter), update)
+}
+
+func (d *TenantScopedDatabase) Delete(ctx context.Context, collection string, filter interface{}) error {
+	return d.IDatabase.Delete(ctx, collection, d.scope(ctx, collection, filter))
+}
+
+func (d *TenantScopedDatabase) DeleteAll(ctx context.Context, collection string, filter interface{}) error {
+	return d.IDatabase.DeleteAll(ctx, collection, d.scope(ctx, collection, filter))
+}
+
+func (d *TenantScopedDatabase) SoftDelete(ctx context.Context, collection string, filter interface{}) error {
+	return d.IDatabase.SoftDelete(ctx, collection, d.scope(ctx, collection, filter))
+}
+
+func (d *TenantScopedDatabase) FindById(ctx context.Context, collection, id string, result interface{}) error {
+	if !d.collections[collection] {
+		return d.IDatabase.FindById(ctx, collection, id, result)
+	}
+	return d.FindOne(ctx, collection, bson.M{"_id": id}, result)
+}
+
+func (d *TenantScopedDatabase) FindOne(ctx context.Context, collection string, filter, result interface{}) error {
+	return d.IDatabase.FindOne(ctx, collection, d.scope(ctx, collection, filter), result)
+}
+
+func (d *TenantScopedDatabase) Find(ctx context.Context, collection string, filter, result interface{}) error {
+	return d.IDatabase.Find(ctx, collection, d.scope(ctx, collection, filter), result)
+}
+
+func (d *TenantScopedDatabase) FindWithPagination(ctx context.Context, collection string, filter interface{}, sortField, sortOrder string, offset, limit int64, result interface{}) error {
+	return d.IDatabase.FindWithPagination(ctx, collection, d.scope(ctx, collection, filter), sortField, sortOrder, offset, limit, result)
+}
+
+func (d *TenantScopedDatabase) FindByTextScore(ctx context.Context, collection string, filter interface{}, offset, limit int64, result interface{}) error {
+	return d.IDatabase.FindByTextScore(ctx, collection, d.scope(ctx, collection, filter), offset, limit, result)
+}
+
+func (d *TenantScopedDatabase) Count(ctx context.Context, collection string, filter interface{}) (int64, error) {
+	return d.IDatabase.Count(ctx, collection, d.scope(ctx, collection, filter))
+}
+
+// Aggregate scopes the filter the stages start from, so that they only see the documents of the tenant.
+func (d *TenantScopedDatabase) Aggregate(ctx context.Context, collection string, filter interface{}, stages []bson.M, result interface{}) error {
+	return d.IDatabase.Aggregate(ctx, collection, d.scope(ctx, collection, filter), stages, result)
+}
+
+// scope adds the tenant condition to the filter of an operation on a scoped collection.
+func (d *TenantScopedDatabase) scope(ctx context.Context, collection string, filter interface{}) interface{} {
+	if !d.collections[collection] || isUnscoped(ctx) {
+		return filter
+	}
+
+	var tenant interface{}
+	if tenantID, ok := TenantFromContext(ctx); ok {
+		tenant = tenantID
+	}
+	condition := bson.M{TenantField: tenant}
+
+	if filter == nil {
+		return condition
+	}
+	return bson.M{"$and": bson.A{filter, condition}}
+}
+
+// stamp sets the tenant of the context on a document created in a scoped collection.
+func (d *TenantScopedDatabase) stamp(ctx context.Context, collection string, doc interface{}) {
+	if !d.collections[collection] || isUnscoped(ctx) {
+		return
+	}
+
+	var tenant *primitive.ObjectID
+	if tenantID, ok := TenantFromContext(ctx); ok {
+		tenant = &tenantID
+	}
+
+	switch owned := doc.(type) {
+	case TenantOwned:
+		owned.SetTenantID(tenant)
+	case bson.M:
+		if tenant != nil {
+			owned[TenantField] = *tenant
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package email
 
 import (
+	"company-name/constants/msgkey"
+	loc "company-name/pkg/localization"
 	"fmt"
 	"net/smtp"
 )
 
 type IEmailService interface {
-	SendVerificationEmail(email, name, verificationLink string) error
+	SendVerificationEmail(email, name, verificationLink, lang string) error
+	SendUserStatusChangedEmail(email, name, status, reason, lang string) error
+	SendInvitationEmail(email, name, invitationLink, expiresAt, lang string) error
 	sendEmail(to, subject, body string) error
 }
 
@@ -28,9 +32,25 @@ func NewEmailService(host, port, username, password, from string) IEmailService
 	}
 }
 
-func (s *Service) SendVerificationEmail(email, name, verificationLink string) error {
-	subject := "Email Verification"
-	body := fmt.Sprintf("Hello %s,\n\nPlease verify your email by clicking the link below:\n%s\n\nThank you.", name, verificationLink)
+// SendVerificationEmail sends the email verification link, written in the given language.
+func (s *Service) SendVerificationEmail(email, name, verificationLink, lang string) error {
+	subject := loc.LFor(lang, msgkey.EmailVerificationSubject)
+	body := loc.LFor(lang, msgkey.EmailVerificationBody, name, verificationLink)
+	return s.sendEmail(email, subject, body)
+}
+
+// SendUserStatusChangedEmail notifies a user that an administrator changed the status of their account, written in
+// the given language.
+func (s *Service) SendUserStatusChangedEmail(email, name, status, reason, lang string) error {
+	subject := loc.LFor(lang, msgkey.EmailStatusChangedSubject)
+	body := loc.LFor(lang, msgkey.EmailStatusChangedBody, name, msgkey.UserStatusPrefix+status, reason)
+	return s.sendEmail(email, subject, body)
+}
+
+// SendInvitationEmail sends the link letting an invited user set their password, written in the given language.
+func (s *Service) SendInvitationEmail(email, name, invitationLink, expiresAt, lang string) error {
+	subject := loc.LFor(lang, msgkey.EmailInvitationSubject)
+	body := loc.LFor(lang, msgkey.EmailInvitationBody, name, invitationLink, expiresAt)
 	return s.sendEmail(email, subject, body)
 }
 
@@ -1,12 +1,44 @@
 package errors
 
 import (
+	"company-name/pkg/utils/etag"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
 func HandleError(c *gin.Context, err error) {
+	var preconditionError *PreconditionFailedError
+	if errors.As(err, &preconditionError) {
+		c.Header("ETag", etag.Format(preconditionError.CurrentVersion))
+		c.JSON(preconditionError.StatusCode(), gin.H{
+			"message":         preconditionError.Message(),
+			"error":           preconditionError.Error(),
+			"current_version": preconditionError.CurrentVersion,
+		})
+		return
+	}
+
+	var inUseError *InUseError
+	if errors.As(err, &inUseError) {
+		c.JSON(inUseError.StatusCode(), gin.H{
+			"message": inUseError.Message(),
+			"error":   inUseError.Error(),
+			"used_by": inUseError.UsedBy,
+		})
+		return
+	}
+
+	// Errors carrying validation errors report them per field, the way request validation does
+	var baseError *BaseError
+	if errors.As(err, &baseError) && len(baseError.ValidationErrors()) > 0 {
+		c.JSON(baseError.StatusCode(), gin.H{
+			"message": baseError.Message(),
+			"errors":  baseError.ValidationErrors(),
+		})
+		return
+	}
+
 	var httpError HttpError
 	if errors.As(err, &httpError) {
 		c.JSON(httpError.StatusCode(), gin.H{
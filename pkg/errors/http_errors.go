@@ -111,9 +111,10 @@ func InternalServerError(err error) *BaseError {
 	return InternalServerErrorM(cons.ErrInternalServerError, err)
 }
 
-// ConflictM creates a new BaseError with a 409 HTTP status code using the provided message key and error details.
-func ConflictM(messageKey string, err error) *BaseError {
-	return NewLocalizedHTTPError(http.StatusConflict, messageKey, err)
+// ConflictM creates a new BaseError with a 409 HTTP status code using the provided message key, filled with the given
+// placeholders, and error details.
+func ConflictM(messageKey string, err error, placeholders ...string) *BaseError {
+	return NewLocalizedHTTPError(http.StatusConflict, messageKey, err, placeholders...)
 }
 
 // Conflict creates a new BaseError with a 409 HTTP status code and the provided error details.
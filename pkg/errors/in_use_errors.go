@@ -0,0 +1,18 @@
+package errors
+
+import "net/http"
+
+// InUseError is returned when a resource cannot be deleted while other resources refer to it, which are carried so
+// that clients can show what still uses it.
+type InUseError struct {
+	*BaseError
+	UsedBy interface{}
+}
+
+// InUse creates an InUseError with a 409 status code and the given localized message, listing what uses the resource.
+func InUse(message string, usedBy interface{}) *InUseError {
+	return &InUseError{
+		BaseError: NewLocalizedHTTPError(http.StatusConflict, message, nil),
+		UsedBy:    usedBy,
+	}
+}
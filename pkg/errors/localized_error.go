@@ -18,10 +18,10 @@ func (e *LocalizedError) Localize() string {
 	return localization.L(e.messageKey)
 }
 
-func NewLocalizedHTTPError(code int, messageKey string, err error) *BaseError {
+func NewLocalizedHTTPError(code int, messageKey string, err error, placeholders ...string) *BaseError {
 	return &BaseError{
 		code:    code,
-		message: localization.L(messageKey),
+		message: localization.L(messageKey, placeholders...),
 		err:     err,
 	}
 }
@@ -0,0 +1,26 @@
+package errors
+
+import (
+	cons "company-name/constants/msgkey"
+	"net/http"
+)
+
+// PreconditionFailedError is returned when the If-Match version of a write does not match the current version of the
+// resource, which is carried so that clients can refresh their copy.
+type PreconditionFailedError struct {
+	*BaseError
+	CurrentVersion int64
+}
+
+// PreconditionFailed creates a PreconditionFailedError with a 412 status code for a resource at the given version.
+func PreconditionFailed(currentVersion int64) *PreconditionFailedError {
+	return &PreconditionFailedError{
+		BaseError:      NewLocalizedHTTPError(http.StatusPreconditionFailed, cons.ErrVersionMismatch, nil),
+		CurrentVersion: currentVersion,
+	}
+}
+
+// PreconditionRequired creates a new BaseError with a 428 status code, returned when a write lacks an If-Match header.
+func PreconditionRequired(err error) *BaseError {
+	return NewLocalizedHTTPError(http.StatusPreconditionRequired, cons.ErrIfMatchRequired, err)
+}
@@ -0,0 +1,138 @@
+package events
+
+import (
+	"sync"
+)
+
+// subscriberBuffer is how many events a subscriber may lag behind before it is dropped.
+const subscriberBuffer = 64
+
+// Event is a message published on a topic. IDs increase with every event published through the broker, whatever its
+// topic, so that subscribers can resume after the last event they received.
+type Event struct {
+	ID    uint64
+	Topic string
+	Name  string
+	Data  interface{}
+}
+
+// Broker fans the events published on a topic out to its subscribers, safe for concurrent use. It keeps the most
+// recent events, of every topic, so that subscribers reconnecting after a short disconnection get the events they
+// missed. Events only reach the subscribers of the process they are published in.
+type Broker struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []Event // Oldest first, up to historySize
+	historySize int
+	subscribers map[string]map[*Subscription]bool
+}
+
+// Subscription receives the events published on a topic until it is closed, or until the broker drops it for falling
+// behind, in which case Events is closed and the subscriber should resubscribe from the last event it received.
+type Subscription struct {
+	Events <-chan Event
+	// Missed lists the events published on the topic after the one the subscription resumes from, oldest first.
+	Missed []Event
+	// Complete is false when events the subscriber resumes after may be missing from Missed, e.g. because they are
+	// older than the kept history or were published before the process restarted.
+	Complete bool
+
+	broker *Broker
+	topic  string
+	events chan Event
+	once   sync.Once
+}
+
+// NewBroker initializes a broker keeping up to historySize events for the subscribers that resume.
+func NewBroker(historySize int) *Broker {
+	return &Broker{
+		historySize: historySize,
+		subscribers: map[string]map[*Subscription]bool{},
+	}
+}
+
+// Publish sends an event to the subscribers of a topic and keeps it for the ones that resume. Subscribers too slow to
+// receive it are dropped rather than blocking the publisher.
+func (b *Broker) Publish(topic, name string, data interface{}) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := Event{ID: b.lastID, Topic: topic, Name: name, Data: data}
+	if b.historySize > 0 {
+		if len(b.history) == b.historySize {
+			b.history = b.history[1:]
+		}
+		b.history = append(b.history, event)
+	}
+
+	for subscription := range b.subscribers[topic] {
+		select {
+		case subscription.events <- event:
+		default:
+			b.remove(subscription)
+		}
+	}
+	return event
+}
+
+// Subscribe starts receiving the events of a topic. Given the ID of the last event received, 0 for none, the events
+// published since then are returned in Missed.
+func (b *Broker) Subscribe(topic string, lastEventID uint64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := make(chan Event, subscriberBuffer)
+	subscription := &Subscription{
+		Events:   events,
+		Complete: true,
+		broker:   b,
+		topic:    topic,
+		events:   events,
+	}
+
+	if lastEventID > 0 {
+		subscription.Complete = b.retainsAfter(lastEventID)
+		for _, event := range b.history {
+			if event.ID > lastEventID && event.Topic == topic {
+				subscription.Missed = append(subscription.Missed, event)
+			}
+		}
+	}
+
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = map[*Subscription]bool{}
+	}
+	b.subscribers[topic][subscription] = true
+	return subscription
+}
+
+// retainsAfter reports whether every event published after the given one is still kept, the lock being held.
+func (b *Broker) retainsAfter(lastEventID uint64) bool {
+	if lastEventID > b.lastID {
+		return false // Published before the process restarted
+	}
+	if len(b.history) == 0 {
+		return lastEventID == b.lastID
+	}
+	return lastEventID+1 >= b.history[0].ID
+}
+
+// Close stops the subscription. Closing it again has no effect.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.broker.remove(s)
+}
+
+// remove drops a subscription and closes its channel, the lock being held.
+func (b *Broker) remove(subscription *Subscription) {
+	subscription.once.Do(func() {
+		delete(b.subscribers[subscription.topic], subscription)
+		if len(b.subscribers[subscription.topic]) == 0 {
+			delete(b.subscribers, subscription.topic)
+		}
+		close(subscription.events)
+	})
+}
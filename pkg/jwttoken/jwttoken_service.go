@@ -2,11 +2,17 @@ package jwttoken
 
 import (
 	"company-name/configs"
+	"errors"
 	"github.com/golang-jwt/jwt/v4"
 	"log"
 	"time"
 )
 
+// tokenTypeClaim names the claim binding a token to a single purpose, such as accepting an invitation.
+const tokenTypeClaim = "token_type"
+
+var ErrUnexpectedTokenType = errors.New("unexpected token type")
+
 // GenerateToken generates a JWT token for user verification
 func GenerateToken(userID string, expirationTime time.Time) string {
 	config := configs.GetConfig()
@@ -64,5 +70,53 @@ func ValidateAccessToken(token string) (jwt.MapClaims, error) {
 		return nil, err
 	}
 
+	// Tokens issued for a single purpose must not grant access to the API
+	if _, typed := claims[tokenTypeClaim]; typed {
+		return nil, ErrUnexpectedTokenType
+	}
+
+	return claims, nil
+}
+
+// GenerateTypedToken generates a JWT token only usable for the purpose named by tokenType. The token id lets the
+// issuer invalidate the token before it expires by no longer accepting its id.
+func GenerateTypedToken(subject, tokenType, tokenID string, expirationTime time.Time) string {
+	config := configs.GetConfig()
+
+	claims := jwt.MapClaims{
+		"sub":          subject,
+		"jti":          tokenID,
+		tokenTypeClaim: tokenType,
+		"exp":          jwt.NewNumericDate(expirationTime),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signedToken, err := token.SignedString([]byte(config.JWT.Secret))
+	if err != nil {
+		log.Fatalf("Error generating JWT token: %v", err)
+	}
+
+	return signedToken
+}
+
+// ValidateTypedToken validates a token generated by GenerateTypedToken for the given purpose.
+func ValidateTypedToken(token, tokenType string) (jwt.MapClaims, error) {
+	config := configs.GetConfig()
+	parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		return []byte(config.JWT.Secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok || !parsedToken.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+
+	if claims[tokenTypeClaim] != tokenType {
+		return nil, ErrUnexpectedTokenType
+	}
+
 	return claims, nil
 }
@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 )
 
+// DefaultLang is the language used when none, or an unsupported one, is requested.
+const DefaultLang = "en"
+
 var (
 	messages map[string]map[string]string
 	lang     string
@@ -53,7 +57,64 @@ func L(key string, placeholders ...string) string {
 	mu.RLock()
 	defer mu.RUnlock()
 
-	if langMessages, ok := messages[lang]; ok {
+	return translate(lang, key, placeholders...)
+}
+
+// LFor translates the key into the given language instead of the current one, falling back to DefaultLang when the
+// language is not supported.
+func LFor(l, key string, placeholders ...string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if _, ok := messages[l]; !ok {
+		l = DefaultLang
+	}
+	return translate(l, key, placeholders...)
+}
+
+// IsSupported reports whether messages were loaded for the given language.
+func IsSupported(l string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	_, ok := messages[l]
+	return ok
+}
+
+// MatchLang returns the first supported language of an Accept-Language header value, comparing base languages so
+// that "ar-EG" matches "ar". It returns an empty string when no language is supported.
+func MatchLang(acceptLanguage string) string {
+	for _, tag := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ = strings.Cut(strings.TrimSpace(tag), ";")
+		base, _, _ := strings.Cut(tag, "-")
+		base = strings.ToLower(base)
+		if IsSupported(base) {
+			return base
+		}
+	}
+	return ""
+}
+
+// FallbackChain lists the languages to try in order when serving content: the base languages of each source in turn,
+// a source being a single language or an Accept-Language header value, then DefaultLang. Unlike MatchLang, it keeps
+// languages for which no messages are loaded, since content may be translated into more languages than the messages.
+func FallbackChain(sources ...string) []string {
+	var chain []string
+	for _, source := range append(sources, DefaultLang) {
+		for _, tag := range strings.Split(source, ",") {
+			tag, _, _ = strings.Cut(strings.TrimSpace(tag), ";")
+			base, _, _ := strings.Cut(tag, "-")
+			base = strings.ToLower(base)
+			if base != "" && base != "*" && !slices.Contains(chain, base) {
+				chain = append(chain, base)
+			}
+		}
+	}
+	return chain
+}
+
+func translate(l, key string, placeholders ...string) string {
+	if langMessages, ok := messages[l]; ok {
 		if msg, ok := langMessages[key]; ok {
 			return replacePlaceholders(msg, langMessages, placeholders...)
 		}
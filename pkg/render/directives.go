@@ -0,0 +1,64 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Kinds of directives expanded in the content of blocks.
+const (
+	DirectiveInclude = "include" // {{include "page/section"}} inserts the content of another block
+	DirectiveVar     = "var"     // {{var "name"}} inserts the value of a variable
+)
+
+var directivePattern = regexp.MustCompile(`\{\{\s*(include|var)\s+"([^"{}]*)"\s*\}\}`)
+
+// Directive is an include or a variable found in the content of a block, with its quoted argument.
+type Directive struct {
+	Kind     string
+	Argument string
+}
+
+// ExpandDirectives replaces every directive of the content by its expansion. Text that merely looks like a directive,
+// e.g. with an unknown kind, is left as written. The first error returned by expand stops the expansion.
+func ExpandDirectives(content string, expand func(Directive) (string, error)) (string, error) {
+	matches := directivePattern.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content, nil
+	}
+
+	var expanded strings.Builder
+	last := 0
+	for _, match := range matches {
+		replacement, err := expand(Directive{Kind: content[match[2]:match[3]], Argument: content[match[4]:match[5]]})
+		if err != nil {
+			return "", err
+		}
+		expanded.WriteString(content[last:match[0]])
+		expanded.WriteString(replacement)
+		last = match[1]
+	}
+	expanded.WriteString(content[last:])
+	return expanded.String(), nil
+}
+
+// Directives lists the directives of the content, in order.
+func Directives(content string) []Directive {
+	var directives []Directive
+	for _, match := range directivePattern.FindAllStringSubmatch(content, -1) {
+		directives = append(directives, Directive{Kind: match[1], Argument: match[2]})
+	}
+	return directives
+}
+
+// ParseInclude splits the argument of an include into the page and section of the included block.
+func ParseInclude(argument string) (page, section string, ok bool) {
+	page, section, ok = strings.Cut(argument, "/")
+	return page, section, ok && page != "" && section != ""
+}
+
+// IncludePattern returns a regular expression matching the directives that include the given block, for searching the
+// stored content.
+func IncludePattern(page, section string) string {
+	return `\{\{\s*include\s+"` + regexp.QuoteMeta(page+"/"+section) + `"\s*\}\}`
+}
@@ -0,0 +1,128 @@
+package render
+
+import (
+	"bytes"
+	"company-name/constants"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	nethtml "golang.org/x/net/html"
+)
+
+// markdown renders CommonMark with GitHub tables, strikethrough and autolinks. Raw HTML embedded in Markdown is left
+// out of the output.
+var markdown = goldmark.New(goldmark.WithExtensions(extension.Table, extension.Strikethrough, extension.Linkify))
+
+// Render converts content written in the given format into the requested rendering. Structured JSON content is returned
+// unchanged whatever the rendering.
+func Render(content, format, mode string) (string, error) {
+	if format == constants.BlockFormatJSON {
+		return content, nil
+	}
+
+	switch mode {
+	case constants.RenderRaw:
+		return content, nil
+	case constants.RenderHTML:
+		return ToHTML(content, format)
+	case constants.RenderText:
+		return ToText(content, format)
+	}
+	return "", fmt.Errorf("unknown rendering %q", mode)
+}
+
+// ToHTML renders Markdown to HTML and escapes plain text. HTML is returned unchanged.
+func ToHTML(content, format string) (string, error) {
+	switch format {
+	case constants.BlockFormatMarkdown:
+		var buffer bytes.Buffer
+		if err := markdown.Convert([]byte(content), &buffer); err != nil {
+			return "", err
+		}
+		return buffer.String(), nil
+	case constants.BlockFormatText:
+		return html.EscapeString(content), nil
+	}
+	return content, nil
+}
+
+// ToText strips the markup of HTML and Markdown content, keeping one line per paragraph or other block element, each
+// with its whitespace collapsed, e.g. for meta descriptions. Plain text is returned unchanged.
+func ToText(content, format string) (string, error) {
+	if format == constants.BlockFormatText {
+		return content, nil
+	}
+
+	rendered, err := ToHTML(content, format)
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	skipped := 0 // Depth inside elements whose text is not content, such as scripts
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(rendered))
+	for {
+		switch tokenizer.Next() {
+		case nethtml.ErrorToken:
+			if tokenizer.Err() != io.EOF {
+				return "", tokenizer.Err()
+			}
+			return collapseLines(text.String()), nil
+		case nethtml.TextToken:
+			if skipped == 0 {
+				text.Write(tokenizer.Text())
+			}
+		case nethtml.StartTagToken:
+			name, _ := tokenizer.TagName()
+			if isSkipped(string(name)) {
+				skipped++
+			}
+			if isBlock(string(name)) {
+				text.WriteString("\n")
+			}
+		case nethtml.EndTagToken:
+			name, _ := tokenizer.TagName()
+			if isSkipped(string(name)) && skipped > 0 {
+				skipped--
+			}
+			if isBlock(string(name)) {
+				text.WriteString("\n")
+			}
+		case nethtml.SelfClosingTagToken:
+			if name, _ := tokenizer.TagName(); isBlock(string(name)) {
+				text.WriteString("\n")
+			}
+		}
+	}
+}
+
+func collapseLines(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isSkipped(tag string) bool {
+	switch tag {
+	case "script", "style", "template", "noscript":
+		return true
+	}
+	return false
+}
+
+func isBlock(tag string) bool {
+	switch tag {
+	case "p", "div", "br", "hr", "li", "ul", "ol", "blockquote", "pre", "table", "tr", "td", "th",
+		"h1", "h2", "h3", "h4", "h5", "h6", "section", "article", "header", "footer":
+		return true
+	}
+	return false
+}
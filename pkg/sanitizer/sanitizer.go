@@ -0,0 +1,174 @@
+package sanitizer
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// AnyTag is the tag name under which a policy lists the attributes allowed on every tag.
+const AnyTag = "*"
+
+// urlAttributes are the attributes holding URLs, whose scheme must be allowed by the policy.
+var urlAttributes = map[string]bool{"href": true, "src": true, "cite": true, "action": true, "formaction": true, "poster": true}
+
+// droppedWithContent are the elements removed along with their content when not allowed, since their content is not
+// text meant to be read.
+var droppedWithContent = map[string]bool{"script": true, "style": true, "iframe": true, "object": true, "embed": true, "template": true, "noscript": true}
+
+// Policy is the allowlist HTML is sanitized against.
+type Policy struct {
+	Tags       map[string]bool
+	Attributes map[string]map[string]bool // Allowed attributes by tag, AnyTag for the ones allowed on every tag
+	URLSchemes map[string]bool            // Relative URLs, without scheme, are always allowed
+}
+
+// ParsePolicy reads a policy from its configuration: comma-separated tags and URL schemes, and attributes given as
+// "tag:attribute,attribute;tag:attribute", with AnyTag as tag for the attributes allowed everywhere.
+func ParsePolicy(tags, attributes, schemes string) Policy {
+	policy := Policy{
+		Tags:       set(tags),
+		Attributes: map[string]map[string]bool{},
+		URLSchemes: set(schemes),
+	}
+	for _, rule := range strings.Split(attributes, ";") {
+		tag, names, ok := strings.Cut(rule, ":")
+		if !ok {
+			continue
+		}
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if policy.Attributes[tag] == nil {
+			policy.Attributes[tag] = map[string]bool{}
+		}
+		for name := range set(names) {
+			policy.Attributes[tag][name] = true
+		}
+	}
+	return policy
+}
+
+func set(list string) map[string]bool {
+	values := map[string]bool{}
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			values[value] = true
+		}
+	}
+	return values
+}
+
+// ISanitizer removes the markup a policy does not allow from HTML.
+type ISanitizer interface {
+	Sanitize(input string) (string, []string)
+}
+
+type Sanitizer struct {
+	policy Policy
+}
+
+func NewSanitizer(policy Policy) ISanitizer {
+	return &Sanitizer{policy: policy}
+}
+
+// Sanitize returns the input without the tags, attributes and URLs the policy does not allow, along with a description
+// of each kind of markup removed, e.g. "<script>" or "<a onclick>". Disallowed tags are removed but their text is kept,
+// except for scripts, styles and embedded objects which are removed entirely. Comments are removed silently.
+func (s *Sanitizer) Sanitize(input string) (string, []string) {
+	var output strings.Builder
+	var rejected []string
+	reject := func(description string) {
+		if !slices.Contains(rejected, description) {
+			rejected = append(rejected, description)
+		}
+	}
+
+	dropping := 0 // Depth inside elements removed with their content
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(input))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == nethtml.ErrorToken {
+			if tokenizer.Err() != io.EOF {
+				reject("malformed markup")
+			}
+			return output.String(), rejected
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case nethtml.TextToken:
+			if dropping == 0 {
+				output.WriteString(html.EscapeString(token.Data))
+			}
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if !s.policy.Tags[token.Data] {
+				reject(fmt.Sprintf("<%s>", token.Data))
+				if droppedWithContent[token.Data] && tokenType == nethtml.StartTagToken {
+					dropping++
+				}
+				continue
+			}
+			if dropping == 0 {
+				output.WriteString(s.startTag(token, tokenType == nethtml.SelfClosingTagToken, reject))
+			}
+		case nethtml.EndTagToken:
+			if !s.policy.Tags[token.Data] {
+				if droppedWithContent[token.Data] && dropping > 0 {
+					dropping--
+				}
+				continue
+			}
+			if dropping == 0 {
+				output.WriteString("</" + token.Data + ">")
+			}
+		}
+	}
+}
+
+// startTag renders an allowed tag with its allowed attributes only.
+func (s *Sanitizer) startTag(token nethtml.Token, selfClosing bool, reject func(string)) string {
+	var tag strings.Builder
+	tag.WriteString("<" + token.Data)
+	for _, attribute := range token.Attr {
+		name := strings.ToLower(attribute.Key)
+		if attribute.Namespace != "" || !(s.policy.Attributes[token.Data][name] || s.policy.Attributes[AnyTag][name]) {
+			reject(fmt.Sprintf("<%s %s>", token.Data, name))
+			continue
+		}
+		if urlAttributes[name] && !s.allowedURL(attribute.Val) {
+			reject(fmt.Sprintf("<%s %s=%q>", token.Data, name, scheme(attribute.Val)+":"))
+			continue
+		}
+		tag.WriteString(fmt.Sprintf(` %s="%s"`, name, html.EscapeString(attribute.Val)))
+	}
+	if selfClosing {
+		tag.WriteString(" /")
+	}
+	tag.WriteString(">")
+	return tag.String()
+}
+
+func (s *Sanitizer) allowedURL(value string) bool {
+	urlScheme := scheme(value)
+	return urlScheme == "" || s.policy.URLSchemes[urlScheme]
+}
+
+// scheme returns the lowercased scheme of a URL, ignoring the whitespace and control characters browsers ignore, or an
+// empty string for relative URLs.
+func scheme(value string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+
+	prefix, _, found := strings.Cut(cleaned, ":")
+	if !found || strings.ContainsAny(prefix, "/?#") {
+		return ""
+	}
+	return strings.ToLower(prefix)
+}
@@ -0,0 +1,93 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// resourceURL names the schema being compiled, which has no location of its own.
+const resourceURL = "urn:block-type"
+
+var printer = message.NewPrinter(language.English)
+
+// Schema is a compiled JSON Schema documents can be validated against.
+type Schema struct {
+	schema *jsonschema.Schema
+}
+
+// Compile parses a JSON Schema, reporting the schemas that are not valid JSON or not valid JSON Schema. References are
+// resolved within the schema only, never fetched.
+func Compile(source string) (*Schema, error) {
+	document, err := jsonschema.UnmarshalJSON(strings.NewReader(source))
+	if err != nil {
+		return nil, err
+	}
+
+	// Without loaders, references to files or URLs are refused instead of being read from the server or the network
+	compiler := jsonschema.NewCompiler()
+	compiler.UseLoader(jsonschema.SchemeURLLoader{})
+	if err := compiler.AddResource(resourceURL, document); err != nil {
+		return nil, err
+	}
+
+	compiled, err := compiler.Compile(resourceURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{schema: compiled}, nil
+}
+
+// Validate checks a JSON document against the schema and returns the violations by JSON pointer to the offending value,
+// the empty pointer standing for the document itself. Several violations of the same value are joined. An error is
+// returned when the document is not valid JSON.
+func (s *Schema) Validate(document string) (map[string]string, error) {
+	instance, err := jsonschema.UnmarshalJSON(strings.NewReader(document))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.schema.Validate(instance)
+	if err == nil {
+		return nil, nil
+	}
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return nil, err
+	}
+
+	violations := map[string]string{}
+	collectViolations(validationErr, violations)
+	return violations, nil
+}
+
+// collectViolations records the errors without causes, the ones that name what is wrong rather than which keyword of
+// the schema failed.
+func collectViolations(validationErr *jsonschema.ValidationError, violations map[string]string) {
+	if len(validationErr.Causes) > 0 {
+		for _, cause := range validationErr.Causes {
+			collectViolations(cause, violations)
+		}
+		return
+	}
+
+	pointer := jsonPointer(validationErr.InstanceLocation)
+	message := validationErr.ErrorKind.LocalizedString(printer)
+	if previous, ok := violations[pointer]; ok {
+		message = fmt.Sprintf("%s; %s", previous, message)
+	}
+	violations[pointer] = message
+}
+
+// jsonPointer formats the path to a value as a JSON pointer, as defined by RFC 6901.
+func jsonPointer(tokens []string) string {
+	var pointer strings.Builder
+	for _, token := range tokens {
+		pointer.WriteString("/")
+		pointer.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return pointer.String()
+}
@@ -0,0 +1,88 @@
+package diff
+
+import "strings"
+
+// Operations of a diff line.
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// Line is a line of a diff: a line kept from the old text, deleted from it, or inserted in the new one.
+type Line struct {
+	Op   string
+	Text string
+}
+
+// Lines compares two texts line by line and returns the edit turning the first into the second. It keeps the longest
+// common subsequence of lines and lists deletions before insertions where lines were replaced.
+func Lines(from, to string) []Line {
+	a, b := splitLines(from), splitLines(to)
+
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]Line, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{OpEqual, a[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, Line{OpDelete, a[i]})
+			i++
+		default:
+			lines = append(lines, Line{OpInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{OpDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{OpInsert, b[j]})
+	}
+
+	return lines
+}
+
+// Unified renders a diff the way `diff -u` does, without hunk headers: one line per entry prefixed with " ", "-" or "+".
+func Unified(lines []Line) string {
+	var builder strings.Builder
+	for _, line := range lines {
+		switch line.Op {
+		case OpDelete:
+			builder.WriteString("-")
+		case OpInsert:
+			builder.WriteString("+")
+		default:
+			builder.WriteString(" ")
+		}
+		builder.WriteString(line.Text)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
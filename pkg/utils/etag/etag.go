@@ -0,0 +1,67 @@
+package etag
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// AnyVersion is returned by Parse for the "*" wildcard, which matches whatever the current version is.
+const AnyVersion int64 = -1
+
+var (
+	ErrInvalidETag = errors.New("invalid entity tag")
+	// ErrMultipleETags is returned by Parse for a list of entity tags: a write expects a single version, so If-Match
+	// only carries one tag or the "*" wildcard.
+	ErrMultipleETags = errors.New("multiple entity tags")
+)
+
+// Format renders a document version as a strong entity tag.
+func Format(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// Parse reads the version out of an If-Match or If-None-Match header value. Weak tags are accepted since versions
+// are only compared for equality. The header must carry a single tag, lists are rejected with ErrMultipleETags.
+func Parse(header string) (int64, error) {
+	value := strings.TrimSpace(header)
+	if value == "*" {
+		return AnyVersion, nil
+	}
+	if strings.Contains(value, ",") {
+		return 0, ErrMultipleETags
+	}
+
+	value = strings.TrimPrefix(value, "W/")
+	value = strings.Trim(value, `"`)
+
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 0 {
+		return 0, ErrInvalidETag
+	}
+	return version, nil
+}
+
+// Matches reports whether the expected version parsed from a header accepts the current version.
+func Matches(expected, current int64) bool {
+	return expected == AnyVersion || expected == current
+}
+
+// FormatWeak renders an opaque value as a weak entity tag, for representations that are equivalent without being
+// identical byte for byte.
+func FormatWeak(value string) string {
+	return `W/"` + value + `"`
+}
+
+// NoneMatch reports whether an If-None-Match header lets the request through, that is whether none of the entity tags
+// it lists, or its "*" wildcard, matches the current tag. Tags are compared weakly, as required for If-None-Match.
+func NoneMatch(header, current string) bool {
+	current = strings.TrimPrefix(current, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == current {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package filerefs
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	idPattern   = regexp.MustCompile(`\b[0-9a-fA-F]{24}\b`)
+	pathPattern = regexp.MustCompile(`[^\s"'<>()\[\]{}]*/[^\s"'<>()\[\]{}]*`)
+)
+
+// Candidates returns what, in the given contents, may refer to a stored file: identifiers, and the paths of links and
+// sources. Files are served under URLs whose scheme, host and leading directories depend on the deployment, so every
+// trailing part of a path is a candidate, e.g. "/static/uploads/a.png" yields "static/uploads/a.png",
+// "uploads/a.png" and "a.png", each also with a leading slash. Candidates are only meant to be looked up among the
+// stored files.
+func Candidates(contents ...string) (paths []string, ids []primitive.ObjectID) {
+	seenPaths := map[string]bool{}
+	seenIDs := map[primitive.ObjectID]bool{}
+
+	for _, content := range contents {
+		for _, match := range idPattern.FindAllString(content, -1) {
+			if id, err := primitive.ObjectIDFromHex(strings.ToLower(match)); err == nil && !seenIDs[id] {
+				seenIDs[id] = true
+				ids = append(ids, id)
+			}
+		}
+
+		for _, match := range pathPattern.FindAllString(content, -1) {
+			path := strings.TrimRight(html.UnescapeString(match), ".,;:!?")
+			if parsed, err := url.Parse(path); err == nil {
+				path = parsed.Path
+			}
+
+			for path = strings.Trim(path, "/"); path != ""; {
+				for _, candidate := range []string{path, "/" + path} {
+					if !seenPaths[candidate] {
+						seenPaths[candidate] = true
+						paths = append(paths, candidate)
+					}
+				}
+
+				_, rest, ok := strings.Cut(path, "/")
+				if !ok {
+					break
+				}
+				path = rest
+			}
+		}
+	}
+	return paths, ids
+}
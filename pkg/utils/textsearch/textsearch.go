@@ -0,0 +1,147 @@
+package textsearch
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Markers wrapped around the matched words by Highlight.
+const (
+	MarkStart = "<mark>"
+	MarkEnd   = "</mark>"
+)
+
+const arabicTatweel = 'ـ'
+
+// Normalize folds case and strips diacritics, Arabic harakat and tatweel included, the way the database text index
+// does, so that highlights agree with what the search matched.
+func Normalize(text string) string {
+	stripMarks := runes.Remove(runes.Predicate(func(r rune) bool {
+		return unicode.Is(unicode.Mn, r) || r == arabicTatweel
+	}))
+
+	result, _, err := transform.String(transform.Chain(norm.NFD, stripMarks, norm.NFC), text)
+	if err != nil {
+		result = text
+	}
+	return strings.ToLower(result)
+}
+
+// Terms splits a search query into the normalized words it looks for. Negated words, prefixed with a dash, are left
+// out since they cannot appear in a match.
+func Terms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		terms = append(terms, strings.FieldsFunc(Normalize(field), func(r rune) bool { return !isWordRune(r) })...)
+	}
+	return terms
+}
+
+// Highlight HTML-escapes the text and wraps the words equal to one of the terms, once normalized, between MarkStart
+// and MarkEnd. It also reports whether any word matched.
+func Highlight(text string, terms []string) (string, bool) {
+	wanted := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		wanted[term] = true
+	}
+
+	var builder strings.Builder
+	matched := false
+	start := -1
+	writeWord := func(end int) {
+		word := html.EscapeString(text[start:end])
+		if wanted[Normalize(text[start:end])] {
+			word = MarkStart + word + MarkEnd
+			matched = true
+		}
+		builder.WriteString(word)
+		start = -1
+	}
+
+	for i, r := range text {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			writeWord(i)
+		}
+		builder.WriteString(html.EscapeString(string(r)))
+	}
+	if start >= 0 {
+		writeWord(len(text))
+	}
+
+	return builder.String(), matched
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || r == arabicTatweel
+}
+
+// Ellipsis marks the text left out around a snippet.
+const Ellipsis = "…"
+
+// Snippet returns the passage of the text around its first word equal to one of the terms, keeping up to radius words
+// on each side, highlighted like Highlight does and with Ellipsis where text was left out. Without any matching word,
+// e.g. when the database matched a stemmed form, the passage starts at the beginning of the text. It also reports
+// whether any word matched.
+func Snippet(text string, terms []string, radius int) (string, bool) {
+	wanted := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		wanted[term] = true
+	}
+
+	type span struct{ start, end int }
+	var words []span
+	first := -1
+	start := -1
+	endWord := func(end int) {
+		if first < 0 && wanted[Normalize(text[start:end])] {
+			first = len(words)
+		}
+		words = append(words, span{start, end})
+		start = -1
+	}
+	for i, r := range text {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+		} else if start >= 0 {
+			endWord(i)
+		}
+	}
+	if start >= 0 {
+		endWord(len(text))
+	}
+	if len(words) == 0 {
+		return "", false
+	}
+
+	from, to := 0, min(len(words)-1, 2*radius)
+	if first >= 0 {
+		from, to = max(0, first-radius), min(len(words)-1, first+radius)
+	}
+
+	var builder strings.Builder
+	if from > 0 {
+		builder.WriteString(Ellipsis)
+	}
+	highlighted, matched := Highlight(strings.Join(strings.Fields(text[words[from].start:words[to].end]), " "), terms)
+	builder.WriteString(highlighted)
+	if to < len(words)-1 {
+		builder.WriteString(Ellipsis)
+	}
+	return builder.String(), matched
+}
@@ -0,0 +1,13 @@
+package validators
+
+import (
+	"company-name/pkg/localization"
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterLanguageValidators adds the "language" tag, satisfied by the languages the localization messages exist in.
+func RegisterLanguageValidators(validate *validator.Validate) {
+	validate.RegisterValidation("language", func(fl validator.FieldLevel) bool {
+		return localization.IsSupported(fl.Field().String())
+	})
+}
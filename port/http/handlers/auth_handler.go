@@ -29,6 +29,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	if !validators.BindJsonAndValidateRequest(c, &loginRequest, h.validator) {
 		return
 	}
+	loginRequest.IPAddress = c.ClientIP()
+	loginRequest.UserAgent = c.Request.UserAgent()
 
 	result, err := h.service.GetToken(c, &loginRequest)
 	if err != nil {
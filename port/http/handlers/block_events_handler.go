@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"company-name/constants"
+	"company-name/internal/content-blocks"
+	"company-name/pkg/events"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+type BlockEventsHandler struct {
+	broker            *events.Broker
+	heartbeatInterval time.Duration
+}
+
+// NewBlockEventsHandler streams the events published through broker, sending a heartbeat after every
+// heartbeatInterval without events.
+func NewBlockEventsHandler(broker *events.Broker, heartbeatInterval time.Duration) *BlockEventsHandler {
+	return &BlockEventsHandler{
+		broker:            broker,
+		heartbeatInterval: heartbeatInterval,
+	}
+}
+
+// StreamPage streams an event, as Server-Sent Events, whenever a block of the page is created, updated or deleted,
+// until the client disconnects. Clients reconnecting with the Last-Event-ID header, or the last_event_id query
+// parameter, first get the events they missed, or a reset event when some may be lost.
+func (h *BlockEventsHandler) StreamPage(c *gin.Context) {
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	// An ID the stream never sent resumes from nothing, like an ID whose events are no longer kept
+	resumeFrom, err := strconv.ParseUint(lastEventID, 10, 64)
+	invalid := lastEventID != "" && err != nil
+
+	subscription := h.broker.Subscribe(blocks.ContextPageTopic(c, c.Param("name")), resumeFrom)
+	defer subscription.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Proxies must not hold events back
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	if !subscription.Complete || invalid {
+		if !h.send(c, sse.Event{Event: constants.BlockEventReset, Data: ""}) {
+			return
+		}
+	}
+	for _, event := range subscription.Missed {
+		if !h.send(c, streamEvent(event)) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return // Dropped for falling behind, the client resumes from its last event when it reconnects
+			}
+			if !h.send(c, streamEvent(event)) {
+				return
+			}
+			heartbeat.Reset(h.heartbeatInterval)
+		case now := <-heartbeat.C:
+			if !h.send(c, sse.Event{Event: constants.BlockEventHeartbeat, Data: now.UTC().Format(time.RFC3339)}) {
+				return
+			}
+		}
+	}
+}
+
+// send writes an event and flushes it to the client, reporting whether the client can still be written to.
+func (h *BlockEventsHandler) send(c *gin.Context, event sse.Event) bool {
+	if err := sse.Encode(c.Writer, event); err != nil {
+		return false
+	}
+	c.Writer.Flush()
+	return true
+}
+
+func streamEvent(event events.Event) sse.Event {
+	return sse.Event{Id: strconv.FormatUint(event.ID, 10), Event: event.Name, Data: event.Data}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"company-name/constants/msgkey"
+	"company-name/internal/block-types"
+	"company-name/internal/block-types/dtos"
+	"company-name/pkg/errors"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/responses"
+	"company-name/pkg/validators"
+	"github.com/gin-gonic/gin"
+)
+
+type BlockTypesHandler struct {
+	service   blocktypes.IBlockTypesService
+	validator validators.IValidator
+}
+
+func NewBlockTypesHandler(service blocktypes.IBlockTypesService, validator validators.IValidator) *BlockTypesHandler {
+	return &BlockTypesHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+func (h *BlockTypesHandler) CreateBlockType(c *gin.Context) {
+	var request dtos.CreateBlockTypeRequest
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	blockType, err := h.service.CreateBlockType(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		blockType.InLocation(location)
+	}
+
+	responses.Created(c, loc.L(msgkey.MsgResourceCreated, msgkey.MsgBlockTypeResource), blockType)
+}
+
+func (h *BlockTypesHandler) UpdateBlockType(c *gin.Context) {
+	var request dtos.UpdateBlockTypeRequest
+	request.Name = c.Param("name")
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	blockType, err := h.service.UpdateBlockType(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		blockType.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceUpdated, msgkey.MsgBlockTypeResource), blockType)
+}
+
+func (h *BlockTypesHandler) DeleteBlockType(c *gin.Context) {
+	var request = dtos.BlockTypeRequest{Name: c.Param("name")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	if err := h.service.DeleteBlockType(c, &request); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.NoContent(c, loc.L(msgkey.MsgResourceDeleted, msgkey.MsgBlockTypeResource))
+}
+
+func (h *BlockTypesHandler) GetBlockType(c *gin.Context) {
+	var request = dtos.BlockTypeRequest{Name: c.Param("name")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	blockType, err := h.service.GetBlockType(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		blockType.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgBlockTypeResource), blockType)
+}
+
+func (h *BlockTypesHandler) GetBlockTypes(c *gin.Context) {
+	blockTypes, err := h.service.GetBlockTypes(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		blockTypes.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgBlockTypeResource), blockTypes)
+}
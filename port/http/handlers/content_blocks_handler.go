@@ -1,30 +1,40 @@
 package handlers
 
 import (
+	"company-name/constants"
 	"company-name/constants/msgkey"
 	"company-name/internal/content-blocks"
 	"company-name/internal/content-blocks/dtos"
+	"company-name/internal/preview"
 	"company-name/pkg/errors"
 	loc "company-name/pkg/localization"
 	"company-name/pkg/responses"
 	"company-name/pkg/validators"
 	"github.com/gin-gonic/gin"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 type ContentBlocksHandler struct {
-	service   blocks.IContentBlocksService
-	validator validators.IValidator
+	service          blocks.IContentBlocksService
+	previews         preview.IPreviewService
+	validator        validators.IValidator
+	pageCacheControl string // Cache-Control of published page responses
 }
 
-func NewContentBlocksHandler(service blocks.IContentBlocksService, validator validators.IValidator) *ContentBlocksHandler {
+func NewContentBlocksHandler(service blocks.IContentBlocksService, previews preview.IPreviewService, validator validators.IValidator, pageCacheControl string) *ContentBlocksHandler {
 	return &ContentBlocksHandler{
-		service:   service,
-		validator: validator,
+		service:          service,
+		previews:         previews,
+		validator:        validator,
+		pageCacheControl: pageCacheControl,
 	}
 }
 
 func (h *ContentBlocksHandler) CreateContentBlock(c *gin.Context) {
 	var request dtos.CreateContentBlockRequest
+	request.AuthorID = c.GetString(constants.ContextUserIDKey)
 
 	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
 		return
@@ -36,22 +46,39 @@ func (h *ContentBlocksHandler) CreateContentBlock(c *gin.Context) {
 		return
 	}
 
+	setETag(c, contentBlock.Version)
+	if location := requestLocation(c); location != nil {
+		contentBlock.InLocation(location)
+	}
+
 	responses.Created(c, loc.L(msgkey.MsgResourceCreated, msgkey.MsgContentBlockResource), contentBlock)
 }
 
 func (h *ContentBlocksHandler) UpdateContentBlock(c *gin.Context) {
 	var request dtos.UpdateContentBlockRequest
+	request.AuthorID = c.GetString(constants.ContextUserIDKey)
 
 	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
 	contentBlock, err := h.service.UpdateBlock(c, &request)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
+	setETag(c, contentBlock.Version)
+	if location := requestLocation(c); location != nil {
+		contentBlock.InLocation(location)
+	}
+
 	responses.Ok(c, loc.L(msgkey.MsgResourceUpdated, msgkey.MsgContentBlockResource), contentBlock)
 }
 
@@ -62,28 +89,70 @@ func (h *ContentBlocksHandler) GetContentBlock(c *gin.Context) {
 		return
 	}
 
+	if !draftsAllowed(c, request.Draft) {
+		return
+	}
+	request.Locales = contentLocales(c, request.Locale)
+
 	contentBlock, err := h.service.GetBlock(c, &request)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
+	setETag(c, contentBlock.Version)
+	setContentLanguage(c, contentBlock.Locale)
+	if location := requestLocation(c); location != nil {
+		contentBlock.InLocation(location)
+	}
+
 	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgContentBlockResource), contentBlock)
 }
 
 func (h *ContentBlocksHandler) GetPageContentBlocks(c *gin.Context) {
-	request := dtos.GetPageContentBlocksRequest{Page: c.Param("name")}
+	draft, _ := strconv.ParseBool(c.Query("draft"))
+	request := dtos.GetPageContentBlocksRequest{Page: c.Param("name"), Draft: draft, Render: c.Query("render")}
 
 	if !validators.ValidateRequestOnly(c, &request, h.validator) {
 		return
 	}
 
+	// Preview links show the drafts of their page to whoever holds them, without an account
+	if token := c.Query("preview"); token != "" {
+		if err := h.previews.AuthorizePreview(c, token, request.Page); err != nil {
+			errors.HandleError(c, err)
+			return
+		}
+		request.Draft = true
+	} else if !draftsAllowed(c, request.Draft) {
+		return
+	}
+	request.Locales = contentLocales(c, c.Query("locale"))
+
 	contentBlocks, err := h.service.GetPage(c, &request)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
+	cacheControl := h.pageCacheControl
+	if request.Draft {
+		cacheControl = constants.DraftPageCacheControl
+	}
+	if notModified(c, contentBlocks.ETag, contentBlocks.LastModified, cacheControl) {
+		return
+	}
+
+	locales := make([]string, 0, len(contentBlocks.Blocks))
+	for _, contentBlock := range contentBlocks.Blocks {
+		locales = append(locales, contentBlock.Locale)
+	}
+	setContentLanguage(c, locales...)
+
+	if location := requestLocation(c); location != nil {
+		contentBlocks.InLocation(location)
+	}
+
 	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgContentBlockResource), contentBlocks)
 }
 
@@ -94,11 +163,257 @@ func (h *ContentBlocksHandler) DeleteContentBlock(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
 	err := h.service.DeleteBlock(c, &request)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		errors.HandleError(c, err)
 		return
 	}
 
 	responses.NoContent(c, loc.L(msgkey.MsgResourceDeleted, msgkey.MsgContentBlockResource))
 }
+
+func (h *ContentBlocksHandler) GetRevisions(c *gin.Context) {
+	var request dtos.GetRevisionsRequest
+
+	if !validators.BindQueryAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	revisions, err := h.service.GetRevisions(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		revisions.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgBlockRevisionResource), revisions)
+}
+
+func (h *ContentBlocksHandler) DiffRevisions(c *gin.Context) {
+	var request dtos.DiffRevisionsRequest
+
+	if !validators.BindQueryAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.DiffRevisions(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgBlockRevisionResource), result)
+}
+
+func (h *ContentBlocksHandler) RollbackRevision(c *gin.Context) {
+	var request dtos.RollbackRevisionRequest
+	request.AuthorID = c.GetString(constants.ContextUserIDKey)
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
+	contentBlock, err := h.service.RollbackRevision(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	setETag(c, contentBlock.Version)
+	if location := requestLocation(c); location != nil {
+		contentBlock.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceUpdated, msgkey.MsgContentBlockResource), contentBlock)
+}
+
+func (h *ContentBlocksHandler) PublishContentBlock(c *gin.Context) {
+	var request dtos.PublishBlockRequest
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
+	contentBlock, err := h.service.PublishBlock(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	message := loc.L(msgkey.MsgBlockPublished)
+	if contentBlock.PublishAt != nil {
+		message = loc.L(msgkey.MsgBlockPublishScheduled)
+	}
+
+	setETag(c, contentBlock.Version)
+	if location := requestLocation(c); location != nil {
+		contentBlock.InLocation(location)
+	}
+
+	responses.Ok(c, message, contentBlock)
+}
+
+func (h *ContentBlocksHandler) UnpublishContentBlock(c *gin.Context) {
+	var request dtos.UnpublishBlockRequest
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
+	contentBlock, err := h.service.UnpublishBlock(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	message := loc.L(msgkey.MsgBlockUnpublished)
+	if contentBlock.UnpublishAt != nil {
+		message = loc.L(msgkey.MsgBlockUnpublishScheduled)
+	}
+
+	setETag(c, contentBlock.Version)
+	if location := requestLocation(c); location != nil {
+		contentBlock.InLocation(location)
+	}
+
+	responses.Ok(c, message, contentBlock)
+}
+
+func (h *ContentBlocksHandler) GetMissingTranslations(c *gin.Context) {
+	var request dtos.MissingTranslationsRequest
+
+	if !validators.BindQueryAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.GetMissingTranslations(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgContentBlockResource), result)
+}
+
+func (h *ContentBlocksHandler) SearchContentBlocks(c *gin.Context) {
+	var request dtos.SearchBlocksRequest
+
+	if !validators.BindQueryAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.SearchBlocks(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		result.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgContentBlockResource), result)
+}
+
+func (h *ContentBlocksHandler) GetDependents(c *gin.Context) {
+	var request dtos.BlockDependentsRequest
+
+	if !validators.BindQueryAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.GetDependents(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgContentBlockResource), result)
+}
+
+func (h *ContentBlocksHandler) ExportBundle(c *gin.Context) {
+	var request dtos.ExportBundleRequest
+	for _, slug := range strings.Split(c.Query("pages"), ",") {
+		if slug = strings.TrimSpace(slug); slug != "" {
+			request.Pages = append(request.Pages, slug)
+		}
+	}
+
+	bundle, err := h.service.ExportBundle(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgContentBundleResource), bundle)
+}
+
+func (h *ContentBlocksHandler) ImportBundle(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	request := dtos.ImportBundleRequest{
+		Mode:     c.DefaultQuery("mode", constants.ImportModeUpsert),
+		DryRun:   dryRun,
+		AuthorID: c.GetString(constants.ContextUserIDKey),
+	}
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.ImportBundle(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	message := loc.L(msgkey.MsgBundleImported)
+	if result.DryRun {
+		message = loc.L(msgkey.MsgBundleImportChecked)
+	}
+
+	responses.Ok(c, message, result)
+}
+
+// draftsAllowed rejects the requests for drafts of anonymous users and of users without an editor role, since only
+// editors may read them.
+func draftsAllowed(c *gin.Context, draft bool) bool {
+	if !draft {
+		return true
+	}
+	if c.GetString(constants.ContextUserIDKey) == "" {
+		errors.HandleError(c, errors.UnauthorizedM(msgkey.ErrMissingToken, nil))
+		return false
+	}
+	if !slices.Contains(constants.EditorRoles, c.GetString(constants.ContextUserRoleKey)) {
+		errors.HandleError(c, errors.ForbiddenM(msgkey.ErrRoleNotAllowed, nil))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"company-name/constants"
+	"company-name/internal/feed"
+	"company-name/pkg/errors"
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Content types of the documents served by FeedHandler.
+const (
+	contentTypeXML  = "application/xml; charset=utf-8"
+	contentTypeAtom = "application/atom+xml; charset=utf-8"
+)
+
+type FeedHandler struct {
+	service      feed.IFeedService
+	cacheControl string
+}
+
+// NewFeedHandler serves the sitemap and the news feed, cached by clients like published pages are.
+func NewFeedHandler(service feed.IFeedService, cacheControl string) *FeedHandler {
+	return &FeedHandler{
+		service:      service,
+		cacheControl: cacheControl,
+	}
+}
+
+func (h *FeedHandler) GetSitemap(c *gin.Context) {
+	sitemap, err := h.service.GetSitemap(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	h.writeXML(c, contentTypeXML, sitemap)
+}
+
+func (h *FeedHandler) GetNewsFeed(c *gin.Context) {
+	newsFeed, err := h.service.GetNewsFeed(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	h.writeXML(c, contentTypeAtom, newsFeed)
+}
+
+// writeXML writes a document, with its XML declaration, under the given content type. The document depends on the
+// tenant the request is scoped to, which caches must tell apart.
+func (h *FeedHandler) writeXML(c *gin.Context, contentType string, document interface{}) {
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		errors.HandleError(c, errors.InternalServerError(err))
+		return
+	}
+
+	c.Header("Cache-Control", h.cacheControl)
+	c.Header("Vary", constants.HeaderTenant)
+	c.Data(http.StatusOK, contentType, append([]byte(xml.Header), body...))
+}
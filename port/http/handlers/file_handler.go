@@ -6,16 +6,28 @@ import (
 	"net/http"
 	"path/filepath"
 
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/internal/files"
+	"company-name/internal/files/dtos"
+	"company-name/pkg/errors"
 	"company-name/pkg/file"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/responses"
+	"company-name/pkg/validators"
 )
 
 type FileHandler struct {
+	service     files.IFileService
 	FileService *file.FileService
+	validator   validators.IValidator
 }
 
-func NewFileHandler(fileService *file.FileService) *FileHandler {
+func NewFileHandler(service files.IFileService, fileService *file.FileService, validator validators.IValidator) *FileHandler {
 	return &FileHandler{
+		service:     service,
 		FileService: fileService,
+		validator:   validator,
 	}
 }
 
@@ -38,27 +50,46 @@ func (h *FileHandler) UploadFile(c *gin.Context) {
 		return
 	}
 
-	filename := filepath.Base(header.Filename) // Ensure a safe filename
-	filePath, err := h.FileService.SaveFile(filename, fileData)
+	request := dtos.UploadFileRequest{
+		FileName: filepath.Base(header.Filename), // Ensure a safe filename
+		Data:     fileData,
+		OwnerID:  c.GetString(constants.ContextUserIDKey),
+	}
+
+	uploaded, err := h.service.UploadFile(c, &request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file: " + err.Error()})
+		errors.HandleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"filePath": filePath})
+	c.JSON(http.StatusOK, gin.H{"id": uploaded.ID, "filePath": uploaded.Path})
 }
 
-func (h *FileHandler) DeleteFile(w http.ResponseWriter, r *http.Request) {
-	filePath := r.URL.Query().Get("filePath")
-	if filePath == "" {
-		http.Error(w, "File path is required", http.StatusBadRequest)
+func (h *FileHandler) DeleteFile(c *gin.Context) {
+	var request = dtos.DeleteFileRequest{ID: c.Param("id")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
 		return
 	}
 
-	if err := h.FileService.DeleteFile(filePath); err != nil {
-		http.Error(w, "Failed to delete file: "+err.Error(), http.StatusInternalServerError)
+	if err := h.service.DeleteFile(c, &request); err != nil {
+		errors.HandleError(c, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	responses.NoContent(c, loc.L(msgkey.MsgResourceDeleted, msgkey.MsgFileResource))
+}
+
+func (h *FileHandler) GetUnreferencedFiles(c *gin.Context) {
+	result, err := h.service.GetUnreferencedFiles(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		result.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgFileResource), result)
 }
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/internal/invitation"
+	"company-name/internal/invitation/dtos"
+	"company-name/pkg/errors"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/responses"
+	"company-name/pkg/validators"
+	"github.com/gin-gonic/gin"
+)
+
+type InvitationHandler struct {
+	service   invitation.IInvitationService
+	validator validators.IValidator
+}
+
+func NewInvitationHandler(service invitation.IInvitationService, validator validators.IValidator) *InvitationHandler {
+	return &InvitationHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+func (h *InvitationHandler) InviteUser(c *gin.Context) {
+	var request dtos.InviteUserRequest
+	request.InvitedBy = c.GetString(constants.ContextUserIDKey)
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.InviteUser(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		result.InLocation(location)
+	}
+
+	responses.Created(c, loc.L(msgkey.MsgInvitationSent), result)
+}
+
+func (h *InvitationHandler) GetPendingInvitations(c *gin.Context) {
+	result, err := h.service.GetPendingInvitations(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		result.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgInvitationResource), result)
+}
+
+func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
+	var request = dtos.InvitationRequest{ID: c.Param("id")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.ResendInvitation(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		result.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgInvitationResent), result)
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	var request = dtos.InvitationRequest{ID: c.Param("id")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	if err := h.service.RevokeInvitation(c, &request); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgInvitationRevoked), nil)
+}
+
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var request dtos.AcceptInvitationRequest
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	if err := h.service.AcceptInvitation(c, &request); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgInvitationAccepted), nil)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/internal/organization"
+	"company-name/internal/organization/dtos"
+	"company-name/pkg/errors"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/responses"
+	"company-name/pkg/validators"
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationHandler struct {
+	service   organization.IOrganizationService
+	validator validators.IValidator
+}
+
+func NewOrganizationHandler(service organization.IOrganizationService, validator validators.IValidator) *OrganizationHandler {
+	return &OrganizationHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var request dtos.CreateOrganizationRequest
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.CreateOrganization(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Created(c, loc.L(msgkey.MsgResourceCreated, msgkey.MsgOrganizationResource), result)
+}
+
+func (h *OrganizationHandler) GetOrganizations(c *gin.Context) {
+	result, err := h.service.GetOrganizations(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgOrganizationResource), result)
+}
+
+func (h *OrganizationHandler) GetMyOrganizations(c *gin.Context) {
+	var request = dtos.GetMyOrganizationsRequest{UserID: c.GetString(constants.ContextUserIDKey)}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.GetMyOrganizations(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgOrganizationResource), result)
+}
+
+func (h *OrganizationHandler) GetMembers(c *gin.Context) {
+	result, err := h.service.GetMembers(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgMembershipResource), result)
+}
+
+func (h *OrganizationHandler) SetMember(c *gin.Context) {
+	var request dtos.SetMemberRequest
+	request.UserID = c.Param("userId")
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.SetMember(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceUpdated, msgkey.MsgMembershipResource), result)
+}
+
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	var request = dtos.MemberRequest{UserID: c.Param("userId")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	if err := h.service.RemoveMember(c, &request); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceDeleted, msgkey.MsgMembershipResource), nil)
+}
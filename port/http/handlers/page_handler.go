@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"company-name/constants/msgkey"
+	"company-name/internal/page"
+	"company-name/internal/page/dtos"
+	"company-name/pkg/errors"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/responses"
+	"company-name/pkg/validators"
+	"github.com/gin-gonic/gin"
+)
+
+type PageHandler struct {
+	service   page.IPageService
+	validator validators.IValidator
+}
+
+func NewPageHandler(service page.IPageService, validator validators.IValidator) *PageHandler {
+	return &PageHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+func (h *PageHandler) CreatePage(c *gin.Context) {
+	var request dtos.CreatePageRequest
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.CreatePage(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		result.InLocation(location)
+	}
+
+	responses.Created(c, loc.L(msgkey.MsgResourceCreated, msgkey.MsgPageResource), result)
+}
+
+func (h *PageHandler) UpdatePage(c *gin.Context) {
+	var request dtos.UpdatePageRequest
+	request.Slug = c.Param("slug")
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.UpdatePage(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		result.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceUpdated, msgkey.MsgPageResource), result)
+}
+
+func (h *PageHandler) ReorderSections(c *gin.Context) {
+	var request dtos.ReorderSectionsRequest
+	request.Slug = c.Param("slug")
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.ReorderSections(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		result.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceUpdated, msgkey.MsgPageResource), result)
+}
+
+func (h *PageHandler) DeletePage(c *gin.Context) {
+	var request = dtos.PageRequest{Slug: c.Param("slug")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	if err := h.service.DeletePage(c, &request); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.NoContent(c, loc.L(msgkey.MsgResourceDeleted, msgkey.MsgPageResource))
+}
+
+func (h *PageHandler) GetPage(c *gin.Context) {
+	var request = dtos.PageRequest{Slug: c.Param("slug")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.GetPage(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		result.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgPageResource), result)
+}
+
+func (h *PageHandler) GetPages(c *gin.Context) {
+	pages, err := h.service.GetPages(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		pages.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgPageResource), pages)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/internal/preview"
+	"company-name/internal/preview/dtos"
+	"company-name/pkg/errors"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/responses"
+	"company-name/pkg/validators"
+	"github.com/gin-gonic/gin"
+)
+
+type PreviewHandler struct {
+	service   preview.IPreviewService
+	validator validators.IValidator
+}
+
+func NewPreviewHandler(service preview.IPreviewService, validator validators.IValidator) *PreviewHandler {
+	return &PreviewHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+func (h *PreviewHandler) CreatePreview(c *gin.Context) {
+	var request dtos.CreatePreviewRequest
+	request.Page = c.Param("slug")
+	request.CreatedBy = c.GetString(constants.ContextUserIDKey)
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.CreatePreview(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		result.InLocation(location)
+	}
+
+	responses.Created(c, loc.L(msgkey.MsgResourceCreated, msgkey.MsgPreviewResource), result)
+}
+
+func (h *PreviewHandler) GetPreviews(c *gin.Context) {
+	var request = dtos.PagePreviewsRequest{Page: c.Param("slug")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	result, err := h.service.GetPreviews(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		result.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgPreviewResource), result)
+}
+
+func (h *PreviewHandler) RevokePreview(c *gin.Context) {
+	var request = dtos.PreviewRequest{Page: c.Param("slug"), ID: c.Param("id")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	if err := h.service.RevokePreview(c, &request); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.NoContent(c, loc.L(msgkey.MsgResourceDeleted, msgkey.MsgPreviewResource))
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/pkg/errors"
+	"company-name/pkg/localization"
+	"company-name/pkg/utils/etag"
+	goerrors "errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestLocation returns the timezone timestamps should be rendered in: the X-Timezone header when valid, otherwise
+// the authenticated user's preferred timezone. It returns nil when neither is known, leaving timestamps untouched.
+func requestLocation(c *gin.Context) *time.Location {
+	for _, name := range []string{c.GetHeader(constants.HeaderTimezone), c.GetString(constants.ContextTimezoneKey)} {
+		if name == "" {
+			continue
+		}
+		if location, err := time.LoadLocation(name); err == nil {
+			return location
+		}
+	}
+	return nil
+}
+
+// ifMatchVersion reads the version a write expects from the If-Match header. Writes without the header are rejected
+// so that clients cannot overwrite changes they have not seen, and so are headers listing several entity tags since a
+// write expects a single version; the error response is written when it returns false.
+func ifMatchVersion(c *gin.Context) (int64, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		errors.HandleError(c, errors.PreconditionRequired(nil))
+		return 0, false
+	}
+
+	version, err := etag.Parse(header)
+	if goerrors.Is(err, etag.ErrMultipleETags) {
+		errors.HandleError(c, errors.BadRequestM(msgkey.ErrIfMatchMultipleTags, err))
+		return 0, false
+	}
+	if err != nil {
+		errors.HandleError(c, errors.BadRequestM(msgkey.ErrInvalidIfMatch, err))
+		return 0, false
+	}
+	return version, true
+}
+
+// setETag exposes the version of the returned resource, to be sent back in the If-Match header of the next write.
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", etag.Format(version))
+}
+
+// notModified sets the validators and caching headers of a cacheable response, then answers the conditional request
+// with 304 Not Modified when the client's copy is still current, in which case it returns true and the response must
+// not be written. If-None-Match takes precedence over If-Modified-Since, as second-precision dates can miss changes.
+func notModified(c *gin.Context, entityTag string, lastModified time.Time, cacheControl string) bool {
+	c.Header("ETag", entityTag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	c.Header("Cache-Control", cacheControl)
+	c.Header("Vary", strings.Join([]string{"Accept-Language", "Authorization", constants.HeaderTenant, constants.HeaderTimezone}, ", "))
+
+	var current bool
+	if header := c.GetHeader("If-None-Match"); header != "" {
+		current = !etag.NoneMatch(header, entityTag)
+	} else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !lastModified.IsZero() {
+		current = !lastModified.Truncate(time.Second).After(since)
+	}
+	if !current {
+		return false
+	}
+
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// contentLocales returns the locales to serve content in, in order of preference: the one explicitly asked for, the
+// languages accepted by the client, the language of the request, e.g. the preferred language of the user, then the
+// default language. Accepted languages come before the language of the request since content may be translated into
+// languages the messages are not.
+func contentLocales(c *gin.Context, explicit string) []string {
+	return localization.FallbackChain(explicit, c.GetHeader("Accept-Language"), c.GetString(constants.ContextLanguageKey))
+}
+
+// setContentLanguage reports the locales the content of the response was served in.
+func setContentLanguage(c *gin.Context, locales ...string) {
+	var served []string
+	for _, locale := range locales {
+		if !slices.Contains(served, locale) {
+			served = append(served, locale)
+		}
+	}
+	if len(served) > 0 {
+		c.Header("Content-Language", strings.Join(served, ", "))
+	}
+}
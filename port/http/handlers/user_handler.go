@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"company-name/constants"
 	"company-name/constants/msgkey"
 	"company-name/internal/user"
 	"company-name/internal/user/dtos"
@@ -8,7 +9,13 @@ import (
 	loc "company-name/pkg/localization"
 	"company-name/pkg/responses"
 	"company-name/pkg/validators"
+	"context"
+	goerrors "errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
 )
 
 type UserHandler struct {
@@ -36,6 +43,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
+	setETag(c, user.Version)
 	responses.Created(c, loc.L(msgkey.MsgResourceCreated, msgkey.MsgUserResource), user)
 }
 
@@ -52,12 +60,19 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
 	user, err := h.service.UpdateUser(c, &request)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
+	setETag(c, user.Version)
 	responses.Ok(c, loc.L(msgkey.MsgResourceUpdated, msgkey.MsgUserResource), user)
 }
 
@@ -68,6 +83,12 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
 	err := h.service.DeleteUser(c, &request)
 	if err != nil {
 		errors.HandleError(c, err)
@@ -93,6 +114,22 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgUserResource), users)
 }
 
+func (h *UserHandler) SearchUsers(c *gin.Context) {
+	var request dtos.SearchUsersRequest
+
+	if !validators.BindQueryAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	users, err := h.service.SearchUsers(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgUserResource), users)
+}
+
 func (h *UserHandler) GetDetailsUserByID(c *gin.Context) {
 	var request = dtos.GetUserDetailsRequest{ID: c.Param("id")}
 
@@ -106,5 +143,186 @@ func (h *UserHandler) GetDetailsUserByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, user.Version)
 	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgUserResource), user)
 }
+
+func (h *UserHandler) BlockUser(c *gin.Context) {
+	h.changeUserStatus(c, h.service.BlockUser, msgkey.MsgUserBlocked)
+}
+
+func (h *UserHandler) UnblockUser(c *gin.Context) {
+	h.changeUserStatus(c, h.service.UnblockUser, msgkey.MsgUserUnblocked)
+}
+
+func (h *UserHandler) ActivateUser(c *gin.Context) {
+	h.changeUserStatus(c, h.service.ActivateUser, msgkey.MsgUserActivated)
+}
+
+func (h *UserHandler) GetUserStatusHistory(c *gin.Context) {
+	var request = dtos.GetUserStatusHistoryRequest{ID: c.Param("id")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	history, err := h.service.GetUserStatusHistory(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		history.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgUserStatusHistoryResource), history)
+}
+
+// changeUserStatus binds the reason of a status change and applies it through the given service action.
+func (h *UserHandler) changeUserStatus(
+	c *gin.Context,
+	action func(ctx context.Context, req *dtos.ChangeUserStatusRequest) (*dtos.ChangeUserStatusResponse, error),
+	messageKey string,
+) {
+	var request dtos.ChangeUserStatusRequest
+	request.ID = c.Param("id")
+	request.ChangedBy = c.GetString(constants.ContextUserIDKey)
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	user, err := action(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(messageKey), user)
+}
+
+func (h *UserHandler) UpdateAvatar(c *gin.Context) {
+	var request = dtos.UpdateAvatarRequest{ID: c.Param("id")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
+	// The body is capped before it is parsed, so that oversized uploads are neither buffered nor written to disk
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constants.AvatarMaxRequestBytes)
+
+	// A missing file leaves Data empty, which the service reports as a validation error
+	file, _, err := c.Request.FormFile("avatar")
+	var tooLarge *http.MaxBytesError
+	if goerrors.As(err, &tooLarge) {
+		errors.HandleError(c, errors.ValidationErrors(map[string]string{"avatar": loc.L(msgkey.ErrAvatarTooLarge, strconv.Itoa(constants.AvatarMaxSizeBytes))}))
+		return
+	}
+	if err == nil {
+		defer file.Close()
+
+		// The file may still exceed the limit within the headroom left for the multipart encoding, reading one byte
+		// past it lets the service reject it
+		data, err := io.ReadAll(io.LimitReader(file, constants.AvatarMaxSizeBytes+1))
+		if err != nil {
+			errors.HandleError(c, errors.BadRequest(err))
+			return
+		}
+		request.Data = data
+	}
+
+	user, err := h.service.UpdateAvatar(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	setETag(c, user.Version)
+	responses.Ok(c, loc.L(msgkey.MsgResourceUpdated, msgkey.MsgAvatarResource), user)
+}
+
+func (h *UserHandler) DeleteAvatar(c *gin.Context) {
+	var request = dtos.DeleteAvatarRequest{ID: c.Param("id")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
+	if err := h.service.DeleteAvatar(c, &request); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.NoContent(c, loc.L(msgkey.MsgResourceDeleted, msgkey.MsgAvatarResource))
+}
+
+func (h *UserHandler) UpdateMyPreferences(c *gin.Context) {
+	var request dtos.UpdatePreferencesRequest
+	request.ID = c.GetString(constants.ContextUserIDKey)
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	request.ExpectedVersion = version
+
+	user, err := h.service.UpdatePreferences(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	setETag(c, user.Version)
+	responses.Ok(c, loc.L(msgkey.MsgResourceUpdated, msgkey.MsgPreferencesResource), user)
+}
+
+func (h *UserHandler) ExportUserData(c *gin.Context) {
+	var request = dtos.ExportUserDataRequest{ID: c.Param("id")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	export, err := h.service.ExportUserData(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%s-data-export.json"`, request.ID))
+	c.JSON(http.StatusOK, export)
+}
+
+func (h *UserHandler) EraseUser(c *gin.Context) {
+	var request dtos.EraseUserRequest
+	request.ID = c.Param("id")
+	request.ErasedBy = c.GetString(constants.ContextUserIDKey)
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	if err := h.service.EraseUser(c, &request); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgUserErased), nil)
+}
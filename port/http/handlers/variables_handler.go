@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"company-name/constants/msgkey"
+	"company-name/internal/variable"
+	"company-name/internal/variable/dtos"
+	"company-name/pkg/errors"
+	loc "company-name/pkg/localization"
+	"company-name/pkg/responses"
+	"company-name/pkg/validators"
+	"github.com/gin-gonic/gin"
+)
+
+type VariablesHandler struct {
+	service   variable.IVariablesService
+	validator validators.IValidator
+}
+
+func NewVariablesHandler(service variable.IVariablesService, validator validators.IValidator) *VariablesHandler {
+	return &VariablesHandler{
+		service:   service,
+		validator: validator,
+	}
+}
+
+func (h *VariablesHandler) CreateVariable(c *gin.Context) {
+	var request dtos.CreateVariableRequest
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	variable, err := h.service.CreateVariable(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		variable.InLocation(location)
+	}
+
+	responses.Created(c, loc.L(msgkey.MsgResourceCreated, msgkey.MsgVariableResource), variable)
+}
+
+func (h *VariablesHandler) UpdateVariable(c *gin.Context) {
+	var request dtos.UpdateVariableRequest
+	request.Name = c.Param("name")
+
+	if !validators.BindJsonAndValidateRequest(c, &request, h.validator) {
+		return
+	}
+
+	variable, err := h.service.UpdateVariable(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		variable.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceUpdated, msgkey.MsgVariableResource), variable)
+}
+
+func (h *VariablesHandler) DeleteVariable(c *gin.Context) {
+	var request = dtos.VariableRequest{Name: c.Param("name")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	if err := h.service.DeleteVariable(c, &request); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	responses.NoContent(c, loc.L(msgkey.MsgResourceDeleted, msgkey.MsgVariableResource))
+}
+
+func (h *VariablesHandler) GetVariable(c *gin.Context) {
+	var request = dtos.VariableRequest{Name: c.Param("name")}
+
+	if !validators.ValidateRequestOnly(c, &request, h.validator) {
+		return
+	}
+
+	variable, err := h.service.GetVariable(c, &request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		variable.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgVariableResource), variable)
+}
+
+func (h *VariablesHandler) GetVariables(c *gin.Context) {
+	variables, err := h.service.GetVariables(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if location := requestLocation(c); location != nil {
+		variables.InLocation(location)
+	}
+
+	responses.Ok(c, loc.L(msgkey.MsgResourceFetched, msgkey.MsgVariableResource), variables)
+}
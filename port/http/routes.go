@@ -1,6 +1,7 @@
 package http
 
 import (
+	"company-name/constants"
 	"company-name/port/http/handlers"
 	"company-name/port/middleware"
 	"github.com/gin-gonic/gin"
@@ -10,34 +11,73 @@ type Router struct {
 	engine               *gin.Engine
 	authHandler          *handlers.AuthHandler
 	contentBlocksHandler *handlers.ContentBlocksHandler
+	blockEventsHandler   *handlers.BlockEventsHandler
+	blockTypesHandler    *handlers.BlockTypesHandler
+	variablesHandler     *handlers.VariablesHandler
+	previewHandler       *handlers.PreviewHandler
+	pageHandler          *handlers.PageHandler
+	feedHandler          *handlers.FeedHandler
 	userHandler          *handlers.UserHandler
 	fileHandler          *handlers.FileHandler
+	invitationHandler    *handlers.InvitationHandler
+	organizationHandler  *handlers.OrganizationHandler
+	authMiddleware       *middleware.AuthMiddleware
+	tenantMiddleware     *middleware.TenantMiddleware
 }
 
 func NewRouter(
 	engine *gin.Engine,
 	authHandler *handlers.AuthHandler,
 	contentBlocksHandler *handlers.ContentBlocksHandler,
+	blockEventsHandler *handlers.BlockEventsHandler,
+	blockTypesHandler *handlers.BlockTypesHandler,
+	variablesHandler *handlers.VariablesHandler,
+	previewHandler *handlers.PreviewHandler,
+	pageHandler *handlers.PageHandler,
+	feedHandler *handlers.FeedHandler,
 	userHandler *handlers.UserHandler,
 	fileHandler *handlers.FileHandler,
+	invitationHandler *handlers.InvitationHandler,
+	organizationHandler *handlers.OrganizationHandler,
+	authMiddleware *middleware.AuthMiddleware,
+	tenantMiddleware *middleware.TenantMiddleware,
 ) *Router {
 	return &Router{
 		engine:               engine,
 		authHandler:          authHandler,
 		contentBlocksHandler: contentBlocksHandler,
+		blockEventsHandler:   blockEventsHandler,
+		blockTypesHandler:    blockTypesHandler,
+		variablesHandler:     variablesHandler,
+		previewHandler:       previewHandler,
+		pageHandler:          pageHandler,
+		feedHandler:          feedHandler,
 		userHandler:          userHandler,
 		fileHandler:          fileHandler,
+		invitationHandler:    invitationHandler,
+		organizationHandler:  organizationHandler,
+		authMiddleware:       authMiddleware,
+		tenantMiddleware:     tenantMiddleware,
 	}
 }
 
 func (r *Router) RegisterRoutes() error {
 	api := r.engine.Group("/api/v1")
-	api.Use(middleware.LocalizationMiddleware)
+	api.Use(middleware.LocalizationMiddleware, r.tenantMiddleware.ResolveTenant)
 	r.registerAuthRoutes(api)
 	r.registerContentBlocksRoutes(api)
+	r.registerBlockTypesRoutes(api)
+	r.registerVariablesRoutes(api)
+	r.registerPagesRoutes(api)
 	r.registerUsersRoutes(api)
 	r.registerPaymentRoutes(api)
 	r.registerFilesRoutes(api)
+	r.registerOrganizationsRoutes(api)
+
+	// Crawlers and feed readers expect these documents at the root of the site
+	site := r.engine.Group("")
+	site.Use(middleware.LocalizationMiddleware, r.tenantMiddleware.ResolveTenant)
+	r.registerFeedRoutes(site)
 
 	return nil
 }
@@ -48,29 +88,129 @@ func (r *Router) registerAuthRoutes(api *gin.RouterGroup) {
 	authRoutes.POST("/login", r.authHandler.Login)
 	authRoutes.POST("/register", r.authHandler.Register)
 	authRoutes.GET("/verify-email", r.authHandler.VerifyEmail)
+	authRoutes.POST("/accept-invite", r.invitationHandler.AcceptInvitation)
 }
 
 func (r *Router) registerContentBlocksRoutes(api *gin.RouterGroup) {
-	blocksRoutes := api.Group("/blocks")
+	blocksRoutes := api.Group("/blocks", r.authMiddleware.OptionalAuthenticate)
 	blocksRoutes.GET("/page/:name", r.contentBlocksHandler.GetPageContentBlocks)
 	blocksRoutes.GET("/", r.contentBlocksHandler.GetContentBlock)
-	blocksRoutes.POST("/", r.contentBlocksHandler.CreateContentBlock)
-	blocksRoutes.PUT("/", r.contentBlocksHandler.UpdateContentBlock)
-	blocksRoutes.DELETE("/", r.contentBlocksHandler.DeleteContentBlock)
+
+	// Writes are attributed to their author in the revision history. Only editors write blocks or read their drafts
+	editorRoutes := api.Group("/blocks", r.authMiddleware.Authenticate, r.authMiddleware.RequireRole(constants.EditorRoles...))
+	editorRoutes.POST("/", r.contentBlocksHandler.CreateContentBlock)
+	editorRoutes.PUT("/", r.contentBlocksHandler.UpdateContentBlock)
+	editorRoutes.DELETE("/", r.contentBlocksHandler.DeleteContentBlock)
+	editorRoutes.POST("/publish", r.contentBlocksHandler.PublishContentBlock)
+	editorRoutes.POST("/unpublish", r.contentBlocksHandler.UnpublishContentBlock)
+	editorRoutes.GET("/revisions", r.contentBlocksHandler.GetRevisions)
+	editorRoutes.GET("/revisions/diff", r.contentBlocksHandler.DiffRevisions)
+	editorRoutes.POST("/revisions/rollback", r.contentBlocksHandler.RollbackRevision)
+	editorRoutes.GET("/translations/missing", r.contentBlocksHandler.GetMissingTranslations)
+	editorRoutes.GET("/dependents", r.contentBlocksHandler.GetDependents)
+	editorRoutes.GET("/search", r.contentBlocksHandler.SearchContentBlocks)
+	editorRoutes.GET("/page/:name/stream", r.blockEventsHandler.StreamPage)
+	editorRoutes.GET("/export", r.contentBlocksHandler.ExportBundle)
+	editorRoutes.POST("/import", r.authMiddleware.RequireRole(constants.UserRoleAdmin), r.contentBlocksHandler.ImportBundle)
+}
+
+func (r *Router) registerFeedRoutes(site *gin.RouterGroup) {
+	site.GET("/sitemap.xml", r.feedHandler.GetSitemap)
+	site.GET("/feed.atom", r.feedHandler.GetNewsFeed)
+}
+
+func (r *Router) registerBlockTypesRoutes(api *gin.RouterGroup) {
+	blockTypeRoutes := api.Group("/block-types", r.authMiddleware.Authenticate)
+	blockTypeRoutes.GET("/", r.blockTypesHandler.GetBlockTypes)
+	blockTypeRoutes.GET("/:name", r.blockTypesHandler.GetBlockType)
+
+	adminRoutes := blockTypeRoutes.Group("", r.authMiddleware.RequireRole(constants.UserRoleAdmin))
+	adminRoutes.POST("/", r.blockTypesHandler.CreateBlockType)
+	adminRoutes.PUT("/:name", r.blockTypesHandler.UpdateBlockType)
+	adminRoutes.DELETE("/:name", r.blockTypesHandler.DeleteBlockType)
+}
+
+func (r *Router) registerVariablesRoutes(api *gin.RouterGroup) {
+	variableRoutes := api.Group("/variables", r.authMiddleware.Authenticate)
+	variableRoutes.GET("/", r.variablesHandler.GetVariables)
+	variableRoutes.GET("/:name", r.variablesHandler.GetVariable)
+
+	adminRoutes := variableRoutes.Group("", r.authMiddleware.RequireRole(constants.UserRoleAdmin))
+	adminRoutes.POST("/", r.variablesHandler.CreateVariable)
+	adminRoutes.PUT("/:name", r.variablesHandler.UpdateVariable)
+	adminRoutes.DELETE("/:name", r.variablesHandler.DeleteVariable)
+}
+
+func (r *Router) registerPagesRoutes(api *gin.RouterGroup) {
+	pageRoutes := api.Group("/pages", r.authMiddleware.Authenticate)
+	pageRoutes.GET("/", r.pageHandler.GetPages)
+	pageRoutes.GET("/:slug", r.pageHandler.GetPage)
+
+	// Only editors compose pages
+	editorRoutes := pageRoutes.Group("", r.authMiddleware.RequireRole(constants.EditorRoles...))
+	editorRoutes.POST("/", r.pageHandler.CreatePage)
+	editorRoutes.PUT("/:slug", r.pageHandler.UpdatePage)
+	editorRoutes.PUT("/:slug/sections", r.pageHandler.ReorderSections)
+	editorRoutes.DELETE("/:slug", r.pageHandler.DeletePage)
+
+	// Preview links reveal the drafts of a page, which only editors may share
+	previewRoutes := pageRoutes.Group("/:slug/previews", r.authMiddleware.RequireRole(constants.EditorRoles...))
+	previewRoutes.POST("", r.previewHandler.CreatePreview)
+	previewRoutes.GET("", r.previewHandler.GetPreviews)
+	previewRoutes.DELETE("/:id", r.previewHandler.RevokePreview)
 }
 
 func (r *Router) registerUsersRoutes(api *gin.RouterGroup) {
 	userRoutes := api.Group("/users")
-	userRoutes.GET("/", r.userHandler.GetAllUsers)
-	userRoutes.GET("/:id", r.userHandler.GetDetailsUserByID)
-	userRoutes.POST("/", r.userHandler.CreateUser)
-	userRoutes.PUT("/:id", r.userHandler.UpdateUser)
-	userRoutes.DELETE("/:id", r.userHandler.DeleteUser)
+
+	readRoutes := userRoutes.Group("", r.authMiddleware.OptionalAuthenticate, r.tenantMiddleware.RequireTenantMember)
+	readRoutes.GET("/", r.userHandler.GetAllUsers)
+	readRoutes.GET("/search", r.userHandler.SearchUsers)
+	readRoutes.GET("/:id", r.userHandler.GetDetailsUserByID)
+
+	userRoutes.POST("/", r.authMiddleware.Authenticate, r.authMiddleware.RequireRole(constants.UserRoleAdmin), r.userHandler.CreateUser)
+	userRoutes.PUT("/:id", r.authMiddleware.Authenticate, r.authMiddleware.RequireRole(constants.UserRoleAdmin), r.userHandler.UpdateUser)
+	userRoutes.DELETE("/:id", r.authMiddleware.Authenticate, r.authMiddleware.RequireRole(constants.UserRoleAdmin), r.userHandler.DeleteUser)
+	userRoutes.PUT("/:id/avatar", r.authMiddleware.Authenticate, r.authMiddleware.RequireSelfOrRole(constants.UserRoleAdmin), r.userHandler.UpdateAvatar)
+	userRoutes.DELETE("/:id/avatar", r.authMiddleware.Authenticate, r.authMiddleware.RequireSelfOrRole(constants.UserRoleAdmin), r.userHandler.DeleteAvatar)
+	userRoutes.PUT("/me/preferences", r.authMiddleware.Authenticate, r.userHandler.UpdateMyPreferences)
+	userRoutes.GET("/:id/data-export", r.authMiddleware.Authenticate, r.authMiddleware.RequireSelfOrRole(constants.UserRoleAdmin), r.userHandler.ExportUserData)
+
+	adminRoutes := userRoutes.Group("/:id", r.authMiddleware.Authenticate, r.authMiddleware.RequireRole(constants.UserRoleAdmin))
+	adminRoutes.POST("/block", r.userHandler.BlockUser)
+	adminRoutes.POST("/unblock", r.userHandler.UnblockUser)
+	adminRoutes.POST("/activate", r.userHandler.ActivateUser)
+	adminRoutes.GET("/status-history", r.userHandler.GetUserStatusHistory)
+	adminRoutes.POST("/erase", r.userHandler.EraseUser)
+
+	invitationRoutes := userRoutes.Group("", r.authMiddleware.Authenticate, r.authMiddleware.RequireRole(constants.UserRoleAdmin))
+	invitationRoutes.POST("/invite", r.invitationHandler.InviteUser)
+	invitationRoutes.GET("/invites", r.invitationHandler.GetPendingInvitations)
+	invitationRoutes.POST("/invites/:id/resend", r.invitationHandler.ResendInvitation)
+	invitationRoutes.DELETE("/invites/:id", r.invitationHandler.RevokeInvitation)
 }
 
 func (r *Router) registerFilesRoutes(api *gin.RouterGroup) {
 	fileRoutes := api.Group("/files")
-	fileRoutes.POST("/", r.fileHandler.UploadFile)
+	fileRoutes.POST("/", r.authMiddleware.OptionalAuthenticate, r.tenantMiddleware.RequireTenantMember, r.fileHandler.UploadFile)
+
+	adminRoutes := fileRoutes.Group("", r.authMiddleware.Authenticate, r.authMiddleware.RequireRole(constants.UserRoleAdmin))
+	adminRoutes.GET("/unreferenced", r.fileHandler.GetUnreferencedFiles)
+	adminRoutes.DELETE("/:id", r.fileHandler.DeleteFile)
+}
+
+func (r *Router) registerOrganizationsRoutes(api *gin.RouterGroup) {
+	organizationRoutes := api.Group("/organizations", r.authMiddleware.Authenticate)
+	organizationRoutes.GET("/mine", r.organizationHandler.GetMyOrganizations)
+
+	platformRoutes := organizationRoutes.Group("", r.authMiddleware.RequireRole(constants.UserRoleAdmin), r.tenantMiddleware.RequirePlatform)
+	platformRoutes.POST("/", r.organizationHandler.CreateOrganization)
+	platformRoutes.GET("/", r.organizationHandler.GetOrganizations)
+
+	memberRoutes := organizationRoutes.Group("/members", r.tenantMiddleware.RequireTenant, r.authMiddleware.RequireRole(constants.UserRoleAdmin))
+	memberRoutes.GET("/", r.organizationHandler.GetMembers)
+	memberRoutes.PUT("/:userId", r.organizationHandler.SetMember)
+	memberRoutes.DELETE("/:userId", r.organizationHandler.RemoveMember)
 }
 
 func (r *Router) registerPaymentRoutes(api *gin.RouterGroup) {
@@ -1 +1,134 @@
 package middleware
+
+import (
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/entities"
+	"company-name/pkg/database"
+	"company-name/pkg/errors"
+	"company-name/pkg/jwttoken"
+	"company-name/pkg/localization"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserFinder loads the user a token was issued for.
+type UserFinder interface {
+	FindByID(ctx context.Context, id string) (*entities.User, error)
+}
+
+// AuthMiddleware authenticates requests using the bearer token of the Authorization header.
+type AuthMiddleware struct {
+	users   UserFinder
+	tenants TenantResolver
+}
+
+func NewAuthMiddleware(users UserFinder, tenants TenantResolver) *AuthMiddleware {
+	return &AuthMiddleware{users: users, tenants: tenants}
+}
+
+// Authenticate rejects requests without a valid token or whose user is blocked, and stores the user id, role and
+// preferences in the context for the next handlers. Requests not naming a tenant are scoped to the tenant of the token,
+// or else to the organization of the user; the role stored is the one the user holds in that tenant.
+func (m *AuthMiddleware) Authenticate(c *gin.Context) {
+	if err := m.authenticate(c); err != nil {
+		errors.HandleError(c, err)
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// OptionalAuthenticate lets anonymous requests through, but authenticates the ones carrying an Authorization header
+// exactly like Authenticate does.
+func (m *AuthMiddleware) OptionalAuthenticate(c *gin.Context) {
+	if c.GetHeader("Authorization") == "" {
+		c.Next()
+		return
+	}
+	m.Authenticate(c)
+}
+
+// RequireRole only lets authenticated users having one of the given roles through. It must run after Authenticate.
+func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(roles, c.GetString(constants.ContextUserRoleKey)) {
+			errors.HandleError(c, errors.ForbiddenM(msgkey.ErrRoleNotAllowed, nil))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSelfOrRole only lets through users acting on themselves, as identified by the "id" route parameter, or
+// having one of the given roles. It must run after Authenticate.
+func (m *AuthMiddleware) RequireSelfOrRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Param("id") == c.GetString(constants.ContextUserIDKey) {
+			c.Next()
+			return
+		}
+		m.RequireRole(roles...)(c)
+	}
+}
+
+func (m *AuthMiddleware) authenticate(c *gin.Context) error {
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return errors.UnauthorizedM(msgkey.ErrMissingToken, nil)
+	}
+
+	claims, err := jwttoken.ValidateAccessToken(token)
+	if err != nil || claims == nil {
+		return errors.UnauthorizedM(msgkey.ErrInvalidToken, err)
+	}
+
+	// The user is looked up across tenants, since the tenant of the request depends on it
+	userID, _ := claims["sub"].(string)
+	user, err := m.users.FindByID(database.WithoutTenantScope(c), userID)
+	if err != nil || user.IsErased() {
+		return errors.UnauthorizedM(msgkey.ErrInvalidToken, err)
+	}
+
+	if user.IsBlocked() {
+		return errors.ForbiddenM(msgkey.ErrUserBlocked, nil)
+	}
+
+	role := user.Role
+	tenantID, scoped := database.TenantFromContext(c)
+	if !scoped {
+		if claim, _ := claims[constants.ContextTenantIDKey].(string); claim != "" {
+			if tenantID, err = primitive.ObjectIDFromHex(claim); err != nil {
+				return errors.UnauthorizedM(msgkey.ErrInvalidToken, err)
+			}
+			scoped = true
+		} else if user.TenantID != nil {
+			tenantID, scoped = *user.TenantID, true
+		}
+	}
+	if scoped {
+		if role, err = m.tenants.RoleIn(c, tenantID, user); err != nil {
+			return errors.ForbiddenM(msgkey.ErrNotTenantMember, err)
+		}
+		c.Set(constants.ContextTenantIDKey, tenantID)
+	}
+
+	c.Set(constants.ContextUserIDKey, user.ID.Hex())
+	c.Set(constants.ContextUserRoleKey, role)
+
+	// The stored language only applies when the request does not ask for one
+	if c.GetHeader("Accept-Language") == "" && localization.IsSupported(user.PreferredLanguage) {
+		localization.SetLang(user.PreferredLanguage)
+		c.Set(constants.ContextLanguageKey, user.PreferredLanguage)
+	}
+	if user.Timezone != "" {
+		c.Set(constants.ContextTimezoneKey, user.Timezone)
+	}
+
+	return nil
+}
@@ -1,16 +1,18 @@
 package middleware
 
 import (
+	"company-name/constants"
 	"company-name/pkg/localization"
 	"github.com/gin-gonic/gin"
 )
 
 func LocalizationMiddleware(c *gin.Context) {
-	lang := c.GetHeader("Accept-Language")
+	lang := localization.MatchLang(c.GetHeader("Accept-Language"))
 	if lang == "" {
-		lang = "en"
+		lang = localization.DefaultLang
 	}
 	localization.SetLang(lang)
+	c.Set(constants.ContextLanguageKey, lang)
 	c.Next()
 	return
 }
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"company-name/constants"
+	"company-name/constants/msgkey"
+	"company-name/entities"
+	"company-name/pkg/database"
+	"company-name/pkg/errors"
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TenantResolver finds the organization a request is scoped to and the role users hold in it.
+type TenantResolver interface {
+	ResolveTenant(ctx context.Context, reference string) (*entities.Organization, error)
+	RoleIn(ctx context.Context, organizationID primitive.ObjectID, user *entities.User) (string, error)
+}
+
+// TenantMiddleware scopes requests to the organization they name.
+type TenantMiddleware struct {
+	tenants TenantResolver
+}
+
+func NewTenantMiddleware(tenants TenantResolver) *TenantMiddleware {
+	return &TenantMiddleware{tenants: tenants}
+}
+
+// ResolveTenant scopes the request to the organization named by the X-Tenant header, by ID or slug, before any
+// repository is reached. Requests without the header are scoped by Authenticate to the tenant of their token, if any.
+// Membership is only checked by Authenticate, so routes anonymous users may reach with private data must also run
+// RequireTenantMember.
+func (m *TenantMiddleware) ResolveTenant(c *gin.Context) {
+	reference := c.GetHeader(constants.HeaderTenant)
+	if reference == "" {
+		c.Next()
+		return
+	}
+
+	organization, err := m.tenants.ResolveTenant(c, reference)
+	if err != nil {
+		errors.HandleError(c, errors.NotFoundM(msgkey.ErrUnknownTenant, err))
+		c.Abort()
+		return
+	}
+
+	c.Set(constants.ContextTenantIDKey, organization.ID)
+	c.Next()
+}
+
+// RequireTenantMember only lets requests naming a tenant with the X-Tenant header through when they are
+// authenticated, Authenticate having checked that the user is a member of it. It must run after Authenticate or
+// OptionalAuthenticate, on routes anonymous users may reach.
+func (m *TenantMiddleware) RequireTenantMember(c *gin.Context) {
+	if c.GetHeader(constants.HeaderTenant) != "" && c.GetString(constants.ContextUserIDKey) == "" {
+		errors.HandleError(c, errors.UnauthorizedM(msgkey.ErrTenantAuthenticationRequired, nil))
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// RequireTenant only lets through requests scoped to an organization.
+func (m *TenantMiddleware) RequireTenant(c *gin.Context) {
+	if _, ok := database.TenantFromContext(c); !ok {
+		errors.HandleError(c, errors.BadRequestM(msgkey.ErrTenantRequired, nil))
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// RequirePlatform only lets through requests that are not scoped to an organization, for actions spanning tenants.
+func (m *TenantMiddleware) RequirePlatform(c *gin.Context) {
+	if _, ok := database.TenantFromContext(c); ok {
+		errors.HandleError(c, errors.ForbiddenM(msgkey.ErrTenantNotAllowed, nil))
+		c.Abort()
+		return
+	}
+	c.Next()
+}